@@ -1,52 +1,106 @@
 package main
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/cloudmigrate/agent/internal/api"
 	"github.com/cloudmigrate/agent/internal/discovery"
+	"github.com/cloudmigrate/agent/internal/discovery/detectors"
+	"github.com/cloudmigrate/agent/internal/discovery/enrich"
+	"github.com/cloudmigrate/agent/internal/discovery/inspect"
+	"github.com/cloudmigrate/agent/internal/discovery/vuln"
+	"github.com/cloudmigrate/agent/internal/progress"
 	"github.com/cloudmigrate/agent/internal/scanner"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var (
-	version   = "1.0.0"
-	serverURL = "https://cloudmigrate.io"
-	apiKey    string
-	watchPath string
+	version      = "1.0.0"
+	serverURL    = "https://cloudmigrate.io"
+	apiKey       string
+	watchPath    string
+	outputFormat string
+	silent       bool
+	noProgress   bool
 )
 
+// validOutputFormats lists the values accepted by --output.
+var validOutputFormats = map[string]bool{"text": true, "json": true, "yaml": true}
+
+// textOutput reports whether informational (non-error) text should be
+// printed: text output hasn't been redirected to JSON/YAML, and --silent
+// wasn't passed.
+func textOutput() bool {
+	return !silent && (outputFormat == "text" || outputFormat == "")
+}
+
+// emit renders data as JSON/YAML when outputFormat requests it, otherwise it
+// calls renderText to fall back to the command's existing human output.
+func emit(data interface{}, renderText func()) error {
+	switch outputFormat {
+	case "", "text":
+		renderText()
+		return nil
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	case "yaml":
+		out, err := yaml.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("marshal yaml: %w", err)
+		}
+		fmt.Print(string(out))
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (want text, json, or yaml)", outputFormat)
+	}
+}
+
 func main() {
 	rootCmd := &cobra.Command{
 		Use:   "cloudmigrate-agent",
 		Short: "CloudMigrate Agent - Local file scanner for cloud migration",
 		Long: `CloudMigrate Agent scans your local files and reports to the CloudMigrate platform.
 This enables the AI assistant to analyze your files and provide migration recommendations.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !validOutputFormats[outputFormat] {
+				return fmt.Errorf("unknown --output %q (want text, json, or yaml)", outputFormat)
+			}
+			return nil
+		},
 	}
 
 	// Connect command - authenticate with the platform
 	connectCmd := &cobra.Command{
 		Use:   "connect",
 		Short: "Connect agent to CloudMigrate platform",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if apiKey == "" {
-				color.Red("Error: API key required. Use --api-key or set CLOUDMIGRATE_API_KEY")
-				os.Exit(1)
+				return fmt.Errorf("API key required. Use --api-key or set CLOUDMIGRATE_API_KEY")
 			}
 
 			client := api.NewClient(serverURL, apiKey)
 			if err := client.Authenticate(); err != nil {
-				color.Red("Authentication failed: %v", err)
-				os.Exit(1)
+				return fmt.Errorf("authentication failed: %w", err)
 			}
 
 			color.Green("âœ“ Connected to CloudMigrate!")
 			fmt.Println("Agent is ready. Use 'cloudmigrate-agent scan' to scan directories.")
+			return nil
 		},
 	}
 
@@ -55,41 +109,47 @@ This enables the AI assistant to analyze your files and provide migration recomm
 		Use:   "scan [path]",
 		Short: "Scan a directory and report to CloudMigrate",
 		Args:  cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			path := "."
 			if len(args) > 0 {
 				path = args[0]
 			}
 
 			if apiKey == "" {
-				color.Red("Error: API key required. Use --api-key or set CLOUDMIGRATE_API_KEY")
-				os.Exit(1)
+				return fmt.Errorf("API key required. Use --api-key or set CLOUDMIGRATE_API_KEY")
 			}
 
-			color.Cyan("Scanning: %s", path)
+			if textOutput() {
+				color.Cyan("Scanning: %s", path)
+			}
+
+			scanOpts := scanner.DefaultScanOptions()
+			scanOpts.Progress = progress.FromFlags(silent, noProgress, outputFormat)
+			stopWatch := progress.WatchInterrupt(scanOpts.Progress)
+			defer stopWatch()
 
-			result, err := scanner.ScanDirectory(path)
+			result, err := scanner.ScanDirectoryWithOptions(path, scanOpts)
 			if err != nil {
-				color.Red("Scan failed: %v", err)
-				os.Exit(1)
+				return fmt.Errorf("scan failed: %w", err)
 			}
 
-			// Print summary
-			color.Green("\nâœ“ Scan complete!")
-			fmt.Printf("  Files:   %d\n", result.FileCount)
-			fmt.Printf("  Folders: %d\n", result.FolderCount)
-			fmt.Printf("  Size:    %s\n", scanner.FormatBytes(result.TotalSize))
-
-			// Upload to server
 			client := api.NewClient(serverURL, apiKey)
 			if err := client.UploadScanResult(result); err != nil {
-				color.Red("Failed to upload results: %v", err)
-				os.Exit(1)
+				return fmt.Errorf("failed to upload results: %w", err)
 			}
 
-			color.Green("âœ“ Results uploaded to CloudMigrate!")
-			fmt.Println("\nYour AI assistant can now analyze these files.")
-			fmt.Println("Try asking: 'What files should I migrate first?'")
+			return emit(result, func() {
+				if silent {
+					return
+				}
+				color.Green("\nâœ“ Scan complete!")
+				fmt.Printf("  Files:   %d\n", result.FileCount)
+				fmt.Printf("  Folders: %d\n", result.FolderCount)
+				fmt.Printf("  Size:    %s\n", scanner.FormatBytes(result.TotalSize))
+				color.Green("âœ“ Results uploaded to CloudMigrate!")
+				fmt.Println("\nYour AI assistant can now analyze these files.")
+				fmt.Println("Try asking: 'What files should I migrate first?'")
+			})
 		},
 	}
 
@@ -139,13 +199,21 @@ The AI assistant can send commands like SCAN, LIST, UPLOAD, DOWNLOAD which the a
 			}
 
 			client := api.NewClient(serverURL, apiKey)
-			
+
 			// Verify connection first
 			if err := client.Authenticate(); err != nil {
 				color.Red("Authentication failed: %v", err)
 				os.Exit(1)
 			}
 
+			// Load operator-defined detectors.yaml once up front, same as the
+			// CLI discover path, so DISCOVER/WATCH_NETWORK commands dispatched
+			// over the life of the daemon see custom detectors too.
+			if err := detectors.LoadCustomDetectors(detectors.DefaultConfigPath()); err != nil {
+				color.Red("Failed to load detectors.yaml: %v", err)
+				os.Exit(1)
+			}
+
 			// Run daemon with 5 second poll interval
 			if err := client.RunDaemon(path, 5*time.Second); err != nil {
 				color.Red("Daemon error: %v", err)
@@ -159,7 +227,7 @@ The AI assistant can send commands like SCAN, LIST, UPLOAD, DOWNLOAD which the a
 		Use:   "list [path]",
 		Short: "List files in a directory",
 		Args:  cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			path := "."
 			if len(args) > 0 {
 				path = args[0]
@@ -167,17 +235,18 @@ The AI assistant can send commands like SCAN, LIST, UPLOAD, DOWNLOAD which the a
 
 			files, err := scanner.ListFiles(path, 50)
 			if err != nil {
-				color.Red("Error: %v", err)
-				os.Exit(1)
+				return err
 			}
 
-			for _, f := range files {
-				if f.IsDir {
-					color.Blue("ðŸ“ %s/", f.Name)
-				} else {
-					fmt.Printf("   %s (%s)\n", f.Name, scanner.FormatBytes(f.Size))
+			return emit(files, func() {
+				for _, f := range files {
+					if f.IsDir {
+						color.Blue("ðŸ“ %s/", f.Name)
+					} else {
+						fmt.Printf("   %s (%s)\n", f.Name, scanner.FormatBytes(f.Size))
+					}
 				}
-			}
+			})
 		},
 	}
 
@@ -185,25 +254,29 @@ The AI assistant can send commands like SCAN, LIST, UPLOAD, DOWNLOAD which the a
 	statusCmd := &cobra.Command{
 		Use:   "status",
 		Short: "Check agent connection status",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if apiKey == "" {
-				color.Yellow("Not connected. Use 'cloudmigrate-agent connect --api-key YOUR_KEY'")
-				return
+				if outputFormat == "text" || outputFormat == "" {
+					color.Yellow("Not connected. Use 'cloudmigrate-agent connect --api-key YOUR_KEY'")
+					return nil
+				}
+				return emit(&api.Status{}, func() {})
 			}
 
 			client := api.NewClient(serverURL, apiKey)
 			status, err := client.GetStatus()
 			if err != nil {
-				color.Red("Error: %v", err)
-				os.Exit(1)
+				return err
 			}
 
-			color.Green("âœ“ Connected")
-			fmt.Printf("  Tenant: %s\n", status.TenantName)
-			fmt.Printf("  Plan:   %s\n", status.Plan)
-			if status.LastScan != "" {
-				fmt.Printf("  Last Scan: %s\n", status.LastScan)
-			}
+			return emit(status, func() {
+				color.Green("âœ“ Connected")
+				fmt.Printf("  Tenant: %s\n", status.TenantName)
+				fmt.Printf("  Plan:   %s\n", status.Plan)
+				if status.LastScan != "" {
+					fmt.Printf("  Last Scan: %s\n", status.LastScan)
+				}
+			})
 		},
 	}
 
@@ -211,8 +284,10 @@ The AI assistant can send commands like SCAN, LIST, UPLOAD, DOWNLOAD which the a
 	versionCmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print agent version",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("CloudMigrate Agent v%s\n", version)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return emit(map[string]string{"version": version}, func() {
+				fmt.Printf("CloudMigrate Agent v%s\n", version)
+			})
 		},
 	}
 
@@ -227,9 +302,16 @@ Examples:
   cloudmigrate-agent discover 192.168.1.0/24    # Scan specific subnet
   cloudmigrate-agent discover 10.0.0.1-254      # Scan IP range`,
 		Args: cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			deepScan, _ := cmd.Flags().GetBool("deep")
 			timeout, _ := cmd.Flags().GetInt("timeout")
+			enableMDNS, _ := cmd.Flags().GetBool("mdns")
+			sshCredsPath, _ := cmd.Flags().GetString("ssh-creds")
+			if sshCredsPath == "" {
+				sshCredsPath = inspect.DefaultConfigPath()
+			}
+			vulnDBDir, _ := cmd.Flags().GetString("vuln-db")
+			vulnOnline, _ := cmd.Flags().GetBool("vuln-online")
 
 			var networks []string
 			if len(args) > 0 {
@@ -239,103 +321,171 @@ Examples:
 				var err error
 				networks, err = discovery.GetLocalNetworks()
 				if err != nil {
-					color.Red("Failed to detect networks: %v", err)
-					os.Exit(1)
+					return fmt.Errorf("failed to detect networks: %w", err)
 				}
 				if len(networks) == 0 {
-					color.Red("No networks found")
-					os.Exit(1)
+					return fmt.Errorf("no networks found")
 				}
 			}
 
-			color.Cyan("ðŸ” CloudMigrate Infrastructure Discovery")
-			color.Cyan("=========================================\n")
+			if textOutput() {
+				color.Cyan("ðŸ” CloudMigrate Infrastructure Discovery")
+				color.Cyan("=========================================\n")
+			}
+
+			creds, err := inspect.LoadCredentials(sshCredsPath)
+			if err != nil {
+				return fmt.Errorf("failed to load ssh-creds: %w", err)
+			}
 
 			opts := discovery.DefaultScanOptions()
 			opts.DeepScan = deepScan
 			opts.Timeout = time.Duration(timeout) * time.Millisecond
+			opts.EnableMDNS = enableMDNS
+			opts.Credentials = creds
+			opts.Progress = progress.FromFlags(silent, noProgress, outputFormat)
+			stopWatch := progress.WatchInterrupt(opts.Progress)
+			defer stopWatch()
+
+			switch {
+			case vulnDBDir != "":
+				db, err := vuln.NewOfflineDatabase(vulnDBDir)
+				if err != nil {
+					return fmt.Errorf("failed to load vuln db: %w", err)
+				}
+				opts.VulnDB = db
+			case vulnOnline:
+				opts.VulnDB = vuln.NewOSVDatabase(5, 1024)
+			}
 
-			scanner := discovery.NewScanner(opts)
+			discScanner := discovery.NewScanner(opts)
 			ctx := context.Background()
 
+			contextRules, err := enrich.LoadRules(enrich.DefaultPath())
+			if err != nil {
+				return fmt.Errorf("failed to load context.yaml: %w", err)
+			}
+
+			if err := detectors.LoadCustomDetectors(detectors.DefaultConfigPath()); err != nil {
+				return fmt.Errorf("failed to load detectors.yaml: %w", err)
+			}
+
+			results := make([]*discovery.DiscoveryResult, 0, len(networks))
+
 			for _, network := range networks {
-				color.Yellow("Scanning network: %s", network)
-				
-				result, err := scanner.ScanNetwork(ctx, network)
-				if err != nil {
-					color.Red("Scan failed: %v", err)
-					continue
+				if textOutput() {
+					color.Yellow("Scanning network: %s", network)
 				}
 
-				// Print results
-				fmt.Println()
-				color.Green("âœ“ Scan complete!")
-				fmt.Printf("  Duration:     %s\n", result.CompletedAt.Sub(result.StartedAt).Round(time.Millisecond))
-				fmt.Printf("  Hosts found:  %d online\n", result.Summary.OnlineHosts)
-				fmt.Println()
-
-				// Print by category
-				if len(result.Summary.ByCategory) > 0 {
-					color.Cyan("By Category:")
-					for cat, count := range result.Summary.ByCategory {
-						fmt.Printf("  %-12s %d\n", cat+":", count)
+				result, err := discScanner.ScanNetwork(ctx, network)
+				if err != nil {
+					if outputFormat == "text" || outputFormat == "" {
+						color.Red("Scan failed: %v", err)
+						continue
 					}
-					fmt.Println()
+					return fmt.Errorf("scan failed for %s: %w", network, err)
 				}
 
-				// Print discovered hosts
-				if len(result.Hosts) > 0 {
-					color.Cyan("Discovered Hosts:")
-					for _, host := range result.Hosts {
-						statusIcon := "ðŸŸ¢"
-						if host.Status != "online" {
-							statusIcon = "ðŸ”´"
-						}
-						
-						hostname := host.Hostname
-						if hostname == "" {
-							hostname = "(no hostname)"
-						}
-
-						fmt.Printf("  %s %-15s  %-20s  %-10s", statusIcon, host.IP, hostname, host.OS)
-						
-						if len(host.Services) > 0 {
-							var svcNames []string
-							for _, svc := range host.Services {
-								svcNames = append(svcNames, svc.Name)
-							}
-							fmt.Printf("  [%s]", joinMax(svcNames, 3))
-						}
-						
-						if host.AWSTarget != "" {
-							color.Green(" â†’ %s", host.AWSTarget)
-						} else {
-							fmt.Println()
-						}
-					}
-				}
+				discovery.ApplyContextRules(result, contextRules)
 
-				// Upload to server if API key provided
 				if apiKey != "" {
 					client := api.NewClient(serverURL, apiKey)
 					if err := client.UploadDiscoveryResult(result); err != nil {
-						color.Yellow("Warning: Failed to upload results: %v", err)
-					} else {
-						color.Green("\nâœ“ Results uploaded to CloudMigrate!")
+						if textOutput() {
+							color.Yellow("Warning: Failed to upload results: %v", err)
+						}
+					} else if textOutput() {
+						color.Green("âœ“ Results uploaded to CloudMigrate!")
 					}
 				}
+
+				results = append(results, result)
 			}
+
+			return emit(results, func() {
+				printDiscoveryResults(results)
+			})
 		},
 	}
 	discoverCmd.Flags().Bool("deep", false, "Perform deep scan with banner grabbing")
 	discoverCmd.Flags().Int("timeout", 2000, "Port scan timeout in milliseconds")
+	discoverCmd.Flags().Bool("mdns", false, "Also discover hosts via passive mDNS/DNS-SD listening")
+	discoverCmd.Flags().String("ssh-creds", "", "Path to ssh-creds.yaml for deep host inspection (default ~/.cloudmigrate/ssh-creds.yaml)")
+	discoverCmd.Flags().String("vuln-db", "", "Directory of offline NVD/endoflife.date feeds for CVE/EOL risk scoring")
+	discoverCmd.Flags().Bool("vuln-online", false, "Query OSV.dev for CVE/EOL risk scoring instead of an offline feed directory")
+
+	// Support command - diagnostics bundle for bug reports
+	supportCmd := &cobra.Command{
+		Use:   "support",
+		Short: "Support diagnostics helpers",
+	}
+
+	supportDumpCmd := &cobra.Command{
+		Use:   "dump [path]",
+		Short: "Bundle diagnostics into a tarball for bug reports",
+		Long: `Collects the resolved config (API key redacted), agent version, OS/arch,
+a fresh scan result, a fresh discovery result, a connectivity probe of the
+configured server, and the tail of the agent log into a single tarball.
+
+Attach the output of this command to bug reports about failed uploads or
+missed hosts during discovery.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			toStdout, _ := cmd.Flags().GetBool("stdout")
+			outPath, _ := cmd.Flags().GetString("file")
+			logLines, _ := cmd.Flags().GetInt("log-lines")
+
+			var out io.Writer
+			var outFile *os.File
+			if toStdout {
+				out = os.Stdout
+			} else {
+				if outPath == "" {
+					outPath = fmt.Sprintf("cloudmigrate-support-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+				}
+				f, err := os.Create(outPath)
+				if err != nil {
+					color.Red("Failed to create %s: %v", outPath, err)
+					os.Exit(1)
+				}
+				outFile = f
+				out = f
+			}
+
+			if err := writeSupportDump(out, path, logLines); err != nil {
+				if outFile != nil {
+					outFile.Close()
+					os.Remove(outPath)
+				}
+				color.Red("Failed to build support dump: %v", err)
+				os.Exit(1)
+			}
+
+			if outFile != nil {
+				outFile.Close()
+				color.Green("✓ Support dump written to %s", outPath)
+			}
+		},
+	}
+	supportDumpCmd.Flags().Bool("stdout", false, "Stream the tarball to stdout instead of writing a file")
+	supportDumpCmd.Flags().String("file", "", "Output path for the tarball (default: cloudmigrate-support-<timestamp>.tar.gz)")
+	supportDumpCmd.Flags().Int("log-lines", 500, "Number of trailing agent log lines to include")
+	supportCmd.AddCommand(supportDumpCmd)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", os.Getenv("CLOUDMIGRATE_API_KEY"), "API key for authentication")
 	rootCmd.PersistentFlags().StringVar(&serverURL, "server", serverURL, "CloudMigrate server URL")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, or yaml")
+	rootCmd.PersistentFlags().BoolVar(&silent, "silent", false, "Suppress all non-error output")
+	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "Disable the progress bar")
 
 	// Add commands
-	rootCmd.AddCommand(connectCmd, scanCmd, watchCmd, daemonCmd, listCmd, statusCmd, versionCmd, discoverCmd)
+	rootCmd.AddCommand(connectCmd, scanCmd, watchCmd, daemonCmd, listCmd, statusCmd, versionCmd, discoverCmd, supportCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -349,3 +499,228 @@ func joinMax(items []string, max int) string {
 	}
 	return strings.Join(items[:max], ", ") + fmt.Sprintf(" +%d more", len(items)-max)
 }
+
+// printDiscoveryResults renders discovery results in the original human
+// text format, one network's scan at a time.
+func printDiscoveryResults(results []*discovery.DiscoveryResult) {
+	for _, result := range results {
+		fmt.Println()
+		color.Green("âœ“ Scan complete!")
+		fmt.Printf("  Duration:     %s\n", result.CompletedAt.Sub(result.StartedAt).Round(time.Millisecond))
+		fmt.Printf("  Hosts found:  %d online\n", result.Summary.OnlineHosts)
+		fmt.Println()
+
+		if len(result.Summary.ByCategory) > 0 {
+			color.Cyan("By Category:")
+			for cat, count := range result.Summary.ByCategory {
+				fmt.Printf("  %-12s %d\n", cat+":", count)
+			}
+			fmt.Println()
+		}
+
+		if len(result.Hosts) > 0 {
+			color.Cyan("Discovered Hosts:")
+			for _, host := range result.Hosts {
+				statusIcon := "ðŸŸ¢"
+				if host.Status != "online" {
+					statusIcon = "ðŸ”´"
+				}
+
+				hostname := host.Hostname
+				if hostname == "" {
+					hostname = "(no hostname)"
+				}
+
+				fmt.Printf("  %s %-15s  %-20s  %-10s", statusIcon, host.IP, hostname, host.OS)
+
+				if len(host.Services) > 0 {
+					var svcNames []string
+					for _, svc := range host.Services {
+						svcNames = append(svcNames, svc.Name)
+					}
+					fmt.Printf("  [%s]", joinMax(svcNames, 3))
+				}
+
+				if host.AWSTarget != "" {
+					color.Green(" â†’ %s", host.AWSTarget)
+				} else {
+					fmt.Println()
+				}
+
+				if len(host.Labels) > 0 {
+					fmt.Printf("      Labels: %s\n", formatLabels(host.Labels))
+				}
+			}
+		}
+	}
+}
+
+// formatLabels renders a label map as sorted key=value pairs for display.
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%s", k, labels[k])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// resolvedConfig is the redacted snapshot of the agent's configuration
+// included in a support dump.
+type resolvedConfig struct {
+	ServerURL string `json:"serverUrl"`
+	APIKey    string `json:"apiKey"`
+	Version   string `json:"version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// writeSupportDump collects diagnostics and writes them as a gzipped tarball to w.
+func writeSupportDump(w io.Writer, path string, logLines int) error {
+	gzw := gzip.NewWriter(w)
+	defer gzw.Close()
+	tw := tar.NewWriter(gzw)
+	defer tw.Close()
+
+	cfg := resolvedConfig{
+		ServerURL: serverURL,
+		APIKey:    redactAPIKey(apiKey),
+		Version:   version,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if err := addJSONFile(tw, "config.json", cfg); err != nil {
+		return err
+	}
+
+	scanResult, scanErr := scanner.ScanDirectory(path)
+	if scanErr != nil {
+		if err := addTextFile(tw, "scan.error.txt", scanErr.Error()); err != nil {
+			return err
+		}
+	} else if err := addJSONFile(tw, "scan.json", scanResult); err != nil {
+		return err
+	}
+
+	discResult, discErr := collectDiscoveryForDump()
+	if discErr != nil {
+		if err := addTextFile(tw, "discovery.error.txt", discErr.Error()); err != nil {
+			return err
+		}
+	} else if err := addJSONFile(tw, "discovery.json", discResult); err != nil {
+		return err
+	}
+
+	status, statusErr := probeStatusForDump()
+	if statusErr != nil {
+		if err := addTextFile(tw, "connectivity.error.txt", statusErr.Error()); err != nil {
+			return err
+		}
+	} else if err := addJSONFile(tw, "status.json", status); err != nil {
+		return err
+	}
+
+	logTail, logErr := tailAgentLog(logLines)
+	if logErr != nil {
+		logTail = fmt.Sprintf("(could not read agent log: %v)", logErr)
+	}
+	if err := addTextFile(tw, "agent.log", logTail); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// collectDiscoveryForDump runs a quick, non-deep discovery scan of the first
+// detected local network so the dump reflects current host visibility.
+func collectDiscoveryForDump() (*discovery.DiscoveryResult, error) {
+	networks, err := discovery.GetLocalNetworks()
+	if err != nil {
+		return nil, fmt.Errorf("detect local networks: %w", err)
+	}
+	if len(networks) == 0 {
+		return nil, fmt.Errorf("no local networks detected")
+	}
+
+	opts := discovery.DefaultScanOptions()
+	scanner := discovery.NewScanner(opts)
+	return scanner.ScanNetwork(context.Background(), networks[0])
+}
+
+// probeStatusForDump checks connectivity to serverURL when an API key is configured.
+func probeStatusForDump() (*api.Status, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("no API key configured, skipping connectivity probe")
+	}
+	client := api.NewClient(serverURL, apiKey)
+	return client.GetStatus()
+}
+
+// redactAPIKey keeps only a short prefix so a dump can be matched to an
+// account without exposing the full secret.
+func redactAPIKey(key string) string {
+	if key == "" {
+		return ""
+	}
+	if len(key) <= 8 {
+		return "****"
+	}
+	return key[:4] + strings.Repeat("*", len(key)-4)
+}
+
+// agentLogPath returns the conventional location of the agent's log file.
+func agentLogPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".cloudmigrate", "agent.log")
+	}
+	return filepath.Join(home, ".cloudmigrate", "agent.log")
+}
+
+// tailAgentLog returns the last n lines of the agent log file, if any.
+func tailAgentLog(n int) (string, error) {
+	data, err := os.ReadFile(agentLogPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "(no agent log found)", nil
+		}
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// addJSONFile writes v as pretty-printed JSON to a file entry in the tarball.
+func addJSONFile(tw *tar.Writer, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", name, err)
+	}
+	return addTextFile(tw, name, string(data))
+}
+
+// addTextFile writes content as a file entry in the tarball.
+func addTextFile(tw *tar.Writer, name, content string) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write header for %s: %w", name, err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
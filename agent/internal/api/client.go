@@ -10,12 +10,16 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/cloudmigrate/agent/internal/commands"
 	"github.com/cloudmigrate/agent/internal/discovery"
+	"github.com/cloudmigrate/agent/internal/log"
 	"github.com/cloudmigrate/agent/internal/scanner"
-	"github.com/fatih/color"
+	"github.com/cloudmigrate/agent/internal/transfer"
+	"github.com/cloudmigrate/agent/internal/watcher"
 	"github.com/gorilla/websocket"
 )
 
@@ -24,6 +28,11 @@ type Client struct {
 	serverURL  string
 	apiKey     string
 	httpClient *http.Client
+	logger     log.Logger
+	registry   *commands.Registry
+
+	transfersMu sync.Mutex
+	transfers   map[string]context.CancelFunc // command id -> cancel func for in-flight UPLOAD/DOWNLOAD/WATCH_NETWORK
 }
 
 // Status represents agent status from server
@@ -34,13 +43,9 @@ type Status struct {
 	Connected  bool   `json:"connected"`
 }
 
-// Command represents a command from the server
-type Command struct {
-	ID      string                 `json:"id"`
-	Type    string                 `json:"type"`
-	Payload map[string]interface{} `json:"payload"`
-	Status  string                 `json:"status"`
-}
+// Command represents a command from the server, dispatched to a
+// commands.Handler by executeCommand.
+type Command = commands.Command
 
 // CommandsResponse represents the response from /api/agent/commands
 type CommandsResponse struct {
@@ -55,6 +60,43 @@ func NewClient(serverURL, apiKey string) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		logger:    log.Default(),
+		registry:  commands.NewDefaultRegistry(),
+		transfers: make(map[string]context.CancelFunc),
+	}
+}
+
+// registerTransfer records the cancel func for an in-flight UPLOAD/DOWNLOAD
+// command so a later CANCEL command or shutdown can stop it.
+func (c *Client) registerTransfer(cmdID string, cancel context.CancelFunc) {
+	c.transfersMu.Lock()
+	defer c.transfersMu.Unlock()
+	c.transfers[cmdID] = cancel
+}
+
+func (c *Client) unregisterTransfer(cmdID string) {
+	c.transfersMu.Lock()
+	defer c.transfersMu.Unlock()
+	delete(c.transfers, cmdID)
+}
+
+// cancelTransfer cancels the in-flight transfer for cmdID, if any, and
+// reports whether one was found.
+func (c *Client) cancelTransfer(cmdID string) bool {
+	c.transfersMu.Lock()
+	defer c.transfersMu.Unlock()
+	cancel, ok := c.transfers[cmdID]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+func (c *Client) cancelAllTransfers() {
+	c.transfersMu.Lock()
+	defer c.transfersMu.Unlock()
+	for _, cancel := range c.transfers {
+		cancel()
 	}
 }
 
@@ -244,9 +286,13 @@ func (c *Client) RunDaemon(basePath string, pollInterval time.Duration) error {
 		return err
 	}
 
-	color.Green("✓ Agent daemon started")
-	color.Cyan("  Watching: %s", absPath)
-	color.Cyan("  Polling every %s", pollInterval)
+	c.logger.Info("agent daemon started", log.F("path", absPath), log.F("poll_interval", pollInterval.String()))
+
+	// daemonCtx is cancelled on SIGINT/SIGTERM so in-flight command
+	// handlers (not just transfers) get a chance to stop early instead of
+	// running to completion after shutdown has been requested.
+	daemonCtx, cancelDaemon := context.WithCancel(context.Background())
+	defer cancelDaemon()
 
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -258,9 +304,9 @@ func (c *Client) RunDaemon(basePath string, pollInterval time.Duration) error {
 		return err
 	}
 	if err := c.UploadScanResult(result); err != nil {
-		color.Yellow("Warning: Failed to upload initial scan: %v", err)
+		c.logger.Warn("failed to upload initial scan", log.F("path", absPath), log.F("error", err.Error()))
 	} else {
-		color.Green("✓ Initial scan uploaded (%d files)", result.FileCount)
+		c.logger.Info("initial scan uploaded", log.F("path", absPath), log.F("file_count", result.FileCount))
 	}
 
 	ticker := time.NewTicker(pollInterval)
@@ -269,138 +315,65 @@ func (c *Client) RunDaemon(basePath string, pollInterval time.Duration) error {
 	for {
 		select {
 		case <-sigChan:
-			color.Yellow("\nShutting down...")
+			c.logger.Info("shutting down")
+			cancelDaemon()
+			c.cancelAllTransfers()
 			return nil
 		case <-ticker.C:
-			commands, err := c.GetCommands()
+			cmds, err := c.GetCommands()
 			if err != nil {
-				color.Yellow("Warning: Failed to poll commands: %v", err)
+				c.logger.Warn("failed to poll commands", log.F("error", err.Error()))
 				continue
 			}
 
-			for _, cmd := range commands {
-				c.executeCommand(cmd, absPath)
+			for _, cmd := range cmds {
+				switch cmd.Type {
+				case "UPLOAD", "DOWNLOAD", "WATCH_NETWORK":
+					// Run in its own goroutine so the poll loop keeps
+					// picking up commands (in particular, a CANCEL for
+					// this same transfer/watch) while it's in flight.
+					go c.executeCommand(daemonCtx, cmd, absPath)
+				default:
+					c.executeCommand(daemonCtx, cmd, absPath)
+				}
 			}
 		}
 	}
 }
 
-// executeCommand executes a single command
-func (c *Client) executeCommand(cmd Command, basePath string) {
-	color.Cyan("→ Executing command: %s (%s)", cmd.Type, cmd.ID[:8])
+// executeCommand runs a single command through the registry and reports
+// its outcome back to the server. ctx is the daemon's lifetime context, so
+// a handler that honors ctx.Done() stops promptly on shutdown.
+func (c *Client) executeCommand(ctx context.Context, cmd Command, basePath string) {
+	logger := c.logger.With(log.F("cmd_id", cmd.ID), log.F("cmd_type", cmd.Type))
+	logger.Info("executing command")
 
 	// Mark as running
 	c.UpdateCommandStatus(cmd.ID, "RUNNING", nil, "")
 
-	var result map[string]interface{}
-	var errMsg string
-
-	switch cmd.Type {
-	case "SCAN":
-		path := basePath
-		if p, ok := cmd.Payload["path"].(string); ok && p != "" {
-			path = p
-		}
-		scanResult, err := scanner.ScanDirectory(path)
-		if err != nil {
-			errMsg = err.Error()
-		} else {
-			c.UploadScanResult(scanResult)
-			result = map[string]interface{}{
-				"fileCount":   scanResult.FileCount,
-				"folderCount": scanResult.FolderCount,
-				"totalSize":   scanResult.TotalSize,
-			}
-		}
-
-	case "LIST":
-		path := basePath
-		if p, ok := cmd.Payload["path"].(string); ok && p != "" {
-			path = p
-		}
-		limit := 100
-		if l, ok := cmd.Payload["limit"].(float64); ok {
-			limit = int(l)
-		}
-		files, err := scanner.ListFiles(path, limit)
-		if err != nil {
-			errMsg = err.Error()
-		} else {
-			result = map[string]interface{}{
-				"files": files,
-				"count": len(files),
-			}
-		}
-
-	case "DISCOVER":
-		// Infrastructure discovery scan
-		networkCIDR := ""
-		if cidr, ok := cmd.Payload["network"].(string); ok && cidr != "" {
-			networkCIDR = cidr
-		}
-		
-		opts := discovery.DefaultScanOptions()
-		if deep, ok := cmd.Payload["deep"].(bool); ok {
-			opts.DeepScan = deep
-		}
-		
-		discScanner := discovery.NewScanner(opts)
-		
-		var networks []string
-		if networkCIDR != "" {
-			networks = []string{networkCIDR}
-		} else {
-			// Auto-detect local networks
-			networks, err = discovery.GetLocalNetworks()
-			if err != nil {
-				errMsg = fmt.Sprintf("Failed to detect networks: %v", err)
-				break
-			}
-		}
-		
-		// Scan each network
-		for _, network := range networks {
-			color.Yellow("  Scanning network: %s", network)
-			discResult, err := discScanner.ScanNetwork(context.Background(), network)
-			if err != nil {
-				errMsg = fmt.Sprintf("Scan failed: %v", err)
-				break
-			}
-			
-			// Upload results
-			if err := c.UploadDiscoveryResult(discResult); err != nil {
-				errMsg = fmt.Sprintf("Failed to upload results: %v", err)
-				break
-			}
-			
-			result = map[string]interface{}{
-				"scanId":      discResult.ScanID,
-				"network":     network,
-				"hostsFound":  discResult.Summary.OnlineHosts,
-				"byCategory":  discResult.Summary.ByCategory,
-			}
-			color.Green("  ✓ Found %d hosts", discResult.Summary.OnlineHosts)
-		}
-
-	case "UPLOAD":
-		// TODO: Implement S3 upload
-		errMsg = "UPLOAD not yet implemented"
-
-	case "DOWNLOAD":
-		// TODO: Implement S3 download
-		errMsg = "DOWNLOAD not yet implemented"
-
-	default:
-		errMsg = fmt.Sprintf("Unknown command type: %s", cmd.Type)
+	ctx = log.WithContext(ctx, logger)
+	deps := commands.Deps{
+		BasePath:              basePath,
+		Logger:                logger,
+		UpdateStatus:          c.UpdateCommandStatus,
+		UploadScanResult:      c.UploadScanResult,
+		UploadDiscoveryResult: c.UploadDiscoveryResult,
+		NewTransferManager:    transfer.NewManager,
+		RegisterTransfer:      c.registerTransfer,
+		UnregisterTransfer:    c.unregisterTransfer,
+		CancelTransfer:        c.cancelTransfer,
 	}
 
-	// Update status
+	result, err := c.registry.Execute(ctx, cmd, deps)
+
 	status := "COMPLETED"
-	if errMsg != "" {
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
 		status = "FAILED"
-		color.Red("  ✗ Command failed: %s", errMsg)
+		logger.Error("command failed", log.F("error", errMsg))
 	} else {
-		color.Green("  ✓ Command completed")
+		logger.Info("command completed")
 	}
 
 	c.UpdateCommandStatus(cmd.ID, status, result, errMsg)
@@ -424,27 +397,62 @@ func (c *Client) WatchDirectory(path string) error {
 	}
 	defer conn.Close()
 
-	color.Green("✓ Connected to CloudMigrate")
+	// conn.WriteJSON/WriteMessage aren't safe for concurrent use, and the
+	// read loop, the fsnotify forwarder, and the keep-alive ticker below
+	// all write to the connection - serialize them through one mutex.
+	var writeMu sync.Mutex
+	writeJSON := func(v interface{}) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteJSON(v)
+	}
+
+	logger := c.logger.With(log.F("path", absPath))
+	logger.Info("connected to CloudMigrate")
 
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	// Initial scan
-	result, err := scanner.ScanDirectory(absPath)
+	scanOpts := scanner.DefaultScanOptions()
+	scanOpts.Logger = logger
+	result, err := scanner.ScanDirectoryWithOptions(absPath, scanOpts)
 	if err != nil {
 		return err
 	}
 
 	// Send initial scan
-	if err := conn.WriteJSON(map[string]interface{}{
+	if err := writeJSON(map[string]interface{}{
 		"type": "scan",
 		"data": result,
 	}); err != nil {
 		return err
 	}
 
-	color.Green("✓ Initial scan uploaded (%d files)", result.FileCount)
+	logger.Info("initial scan uploaded", log.F("file_count", result.FileCount))
+
+	// Stream real-time filesystem changes instead of waiting on the
+	// server to ask for a full rescan, batched into one "delta" message
+	// per debounce window rather than one message per event.
+	fsWatcher, err := watcher.New(absPath, watcher.Options{})
+	if err != nil {
+		logger.Warn("filesystem watch disabled", log.F("error", err.Error()))
+	} else {
+		defer fsWatcher.Close()
+		go func() {
+			for batch := range fsWatcher.Batches() {
+				if err := writeJSON(map[string]interface{}{
+					"type":     "delta",
+					"added":    batch.Added,
+					"modified": batch.Modified,
+					"removed":  batch.Removed,
+				}); err != nil {
+					return
+				}
+			}
+		}()
+	}
 
 	// Listen for commands from server
 	go func() {
@@ -460,13 +468,13 @@ func (c *Client) WatchDirectory(path string) error {
 				path := msg["path"].(string)
 				files, err := scanner.ListFiles(path, 100)
 				if err != nil {
-					conn.WriteJSON(map[string]interface{}{
+					writeJSON(map[string]interface{}{
 						"type":  "error",
 						"error": err.Error(),
 					})
 					continue
 				}
-				conn.WriteJSON(map[string]interface{}{
+				writeJSON(map[string]interface{}{
 					"type":  "files",
 					"files": files,
 				})
@@ -476,7 +484,7 @@ func (c *Client) WatchDirectory(path string) error {
 				filePath := msg["path"].(string)
 				info, err := os.Stat(filePath)
 				if err != nil || info.Size() > 1024*1024 { // Max 1MB
-					conn.WriteJSON(map[string]interface{}{
+					writeJSON(map[string]interface{}{
 						"type":  "error",
 						"error": "file too large or not found",
 					})
@@ -484,33 +492,34 @@ func (c *Client) WatchDirectory(path string) error {
 				}
 				content, err := os.ReadFile(filePath)
 				if err != nil {
-					conn.WriteJSON(map[string]interface{}{
+					writeJSON(map[string]interface{}{
 						"type":  "error",
 						"error": err.Error(),
 					})
 					continue
 				}
-				conn.WriteJSON(map[string]interface{}{
+				writeJSON(map[string]interface{}{
 					"type":    "content",
 					"path":    filePath,
 					"content": string(content),
 				})
 
 			case "rescan":
-				// Server requesting rescan
-				result, err := scanner.ScanDirectory(absPath)
+				// Server requesting a full rescan (still supported as a
+				// fallback alongside the fsnotify-driven delta stream)
+				result, err := scanner.ScanDirectoryWithOptions(absPath, scanOpts)
 				if err != nil {
-					conn.WriteJSON(map[string]interface{}{
+					writeJSON(map[string]interface{}{
 						"type":  "error",
 						"error": err.Error(),
 					})
 					continue
 				}
-				conn.WriteJSON(map[string]interface{}{
+				writeJSON(map[string]interface{}{
 					"type": "scan",
 					"data": result,
 				})
-				color.Cyan("↻ Rescan completed")
+				logger.Info("rescan completed", log.F("file_count", result.FileCount))
 			}
 		}
 	}()
@@ -522,11 +531,16 @@ func (c *Client) WatchDirectory(path string) error {
 	for {
 		select {
 		case <-sigChan:
-			color.Yellow("\nShutting down...")
+			logger.Info("shutting down")
+			writeMu.Lock()
 			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			writeMu.Unlock()
 			return nil
 		case <-ticker.C:
-			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			writeMu.Lock()
+			err := conn.WriteMessage(websocket.PingMessage, nil)
+			writeMu.Unlock()
+			if err != nil {
 				return fmt.Errorf("connection lost: %w", err)
 			}
 		}
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+)
+
+// cancelHandler implements the CANCEL command: it looks up the in-flight
+// UPLOAD/DOWNLOAD/WATCH_NETWORK named by payload["commandId"] and cancels it.
+type cancelHandler struct{}
+
+func (cancelHandler) Type() string { return "CANCEL" }
+
+func (cancelHandler) Validate(payload map[string]interface{}) error {
+	targetID, _ := payload["commandId"].(string)
+	if targetID == "" {
+		return fmt.Errorf("CANCEL command missing commandId")
+	}
+	return nil
+}
+
+func (cancelHandler) Execute(ctx context.Context, cmd Command, deps Deps) (map[string]interface{}, error) {
+	targetID := cmd.Payload["commandId"].(string)
+	found := deps.CancelTransfer(targetID)
+	return map[string]interface{}{
+		"commandId": targetID,
+		"cancelled": found,
+	}, nil
+}
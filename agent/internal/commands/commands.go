@@ -0,0 +1,122 @@
+// Package commands turns server-dispatched commands (SCAN, LIST, DISCOVER,
+// UPLOAD, DOWNLOAD, CANCEL, WATCH_NETWORK, ...) into a Registry of independent Handlers
+// instead of one ever-growing switch statement, mirroring the pattern LXD
+// used when it moved its operations into their own package. Client supplies
+// its capabilities (status updates, uploads, transfer lifecycle) through
+// Deps so this package has no import-time dependency on api.
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudmigrate/agent/internal/discovery"
+	"github.com/cloudmigrate/agent/internal/log"
+	"github.com/cloudmigrate/agent/internal/scanner"
+	"github.com/cloudmigrate/agent/internal/transfer"
+)
+
+// Command represents a command dispatched from the server to a Handler.
+type Command struct {
+	ID      string                 `json:"id"`
+	Type    string                 `json:"type"`
+	Payload map[string]interface{} `json:"payload"`
+	Status  string                 `json:"status"`
+}
+
+// Handler executes one Command.Type. Handlers are registered with a
+// Registry by Type() rather than matched in a switch statement, so adding
+// one doesn't require touching the dispatch code.
+type Handler interface {
+	// Type is the Command.Type this handler is registered for, e.g. "SCAN".
+	Type() string
+
+	// Validate checks payload before Execute runs, so a malformed command
+	// fails fast with a clear error instead of partway through Execute.
+	Validate(payload map[string]interface{}) error
+
+	// Execute runs the command and returns its result (merged into the
+	// COMPLETED status) or an error (reported as FAILED). Long-running
+	// handlers must return promptly once ctx is Done, and may call
+	// deps.UpdateStatus with "RUNNING" to stream partial results.
+	Execute(ctx context.Context, cmd Command, deps Deps) (map[string]interface{}, error)
+}
+
+// Deps are the Client capabilities a Handler needs, passed in rather than
+// imported directly so this package stays decoupled from api.
+type Deps struct {
+	// BasePath is the agent's working directory; handlers resolve
+	// relative payload paths against it.
+	BasePath string
+	Logger   log.Logger
+
+	UpdateStatus          func(cmdID, status string, result map[string]interface{}, errMsg string) error
+	UploadScanResult      func(result *scanner.ScanResult) error
+	UploadDiscoveryResult func(result *discovery.DiscoveryResult) error
+	NewTransferManager    func(ctx context.Context, endpoint, region, stateDir string) (*transfer.Manager, error)
+
+	// RegisterTransfer/UnregisterTransfer/CancelTransfer back the CANCEL
+	// command: a long-running UPLOAD/DOWNLOAD registers its cancel func
+	// under cmd.ID, and CANCEL looks it up by the target command's ID.
+	RegisterTransfer   func(cmdID string, cancel context.CancelFunc)
+	UnregisterTransfer func(cmdID string)
+	CancelTransfer     func(cmdID string) bool
+}
+
+// Registry looks up the Handler registered for a Command's Type.
+type Registry struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{handlers: make(map[string]Handler)}
+}
+
+// NewDefaultRegistry returns a Registry with every built-in handler
+// registered: SCAN, LIST, DISCOVER, UPLOAD, DOWNLOAD, CANCEL, WATCH_NETWORK.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(scanHandler{})
+	r.Register(listHandler{})
+	r.Register(discoverHandler{})
+	r.Register(transferHandler{upload: true})
+	r.Register(transferHandler{upload: false})
+	r.Register(cancelHandler{})
+	r.Register(watchNetworkHandler{})
+	return r
+}
+
+// Register adds h, replacing any existing handler for the same Type.
+func (r *Registry) Register(h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[h.Type()] = h
+}
+
+// Execute validates and runs the handler registered for cmd.Type. When
+// cmd.Payload["timeoutSec"] is set, ctx is wrapped with that timeout so the
+// handler is cancelled if it runs long.
+func (r *Registry) Execute(ctx context.Context, cmd Command, deps Deps) (map[string]interface{}, error) {
+	r.mu.RLock()
+	h, ok := r.handlers[cmd.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown command type: %s", cmd.Type)
+	}
+
+	if err := h.Validate(cmd.Payload); err != nil {
+		return nil, err
+	}
+
+	if secs, ok := cmd.Payload["timeoutSec"].(float64); ok && secs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(secs*float64(time.Second)))
+		defer cancel()
+	}
+
+	return h.Execute(ctx, cmd, deps)
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudmigrate/agent/internal/discovery"
+	"github.com/cloudmigrate/agent/internal/discovery/enrich"
+	"github.com/cloudmigrate/agent/internal/discovery/inspect"
+	"github.com/cloudmigrate/agent/internal/discovery/vuln"
+	"github.com/cloudmigrate/agent/internal/log"
+)
+
+// discoverHandler implements the DISCOVER command: scan one or more
+// networks for infrastructure and upload the results. If payload["network"]
+// is unset, it scans every locally detected network in turn.
+type discoverHandler struct{}
+
+func (discoverHandler) Type() string { return "DISCOVER" }
+
+func (discoverHandler) Validate(payload map[string]interface{}) error { return nil }
+
+func (discoverHandler) Execute(ctx context.Context, cmd Command, deps Deps) (map[string]interface{}, error) {
+	networkCIDR, _ := cmd.Payload["network"].(string)
+
+	opts := discovery.DefaultScanOptions()
+	if deep, ok := cmd.Payload["deep"].(bool); ok {
+		opts.DeepScan = deep
+	}
+	opts.Progress = &commandProgress{update: deps.UpdateStatus, cmdID: cmd.ID}
+
+	credsPath := inspect.DefaultConfigPath()
+	if p, ok := cmd.Payload["sshCredsPath"].(string); ok && p != "" {
+		credsPath = p
+	}
+	creds, err := inspect.LoadCredentials(credsPath)
+	if err != nil {
+		deps.Logger.Warn("failed to load ssh-creds", log.F("error", err.Error()))
+	}
+	opts.Credentials = creds
+
+	switch {
+	case cmd.Payload["vulnDbDir"] != nil:
+		if dir, ok := cmd.Payload["vulnDbDir"].(string); ok && dir != "" {
+			db, err := vuln.NewOfflineDatabase(dir)
+			if err != nil {
+				deps.Logger.Warn("failed to load vuln db", log.F("error", err.Error()))
+			} else {
+				opts.VulnDB = db
+			}
+		}
+	case cmd.Payload["vulnOnline"] == true:
+		opts.VulnDB = vuln.NewOSVDatabase(5, 1024)
+	}
+
+	discScanner := discovery.NewScanner(opts)
+
+	contextRules, err := enrich.LoadRules(enrich.DefaultPath())
+	if err != nil {
+		deps.Logger.Warn("failed to load context.yaml", log.F("error", err.Error()))
+	}
+
+	networks := []string{networkCIDR}
+	if networkCIDR == "" {
+		networks, err = discovery.GetLocalNetworks()
+		if err != nil {
+			return nil, fmt.Errorf("detect networks: %w", err)
+		}
+	}
+
+	var result map[string]interface{}
+	for _, network := range networks {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		netLogger := deps.Logger.With(log.F("network", network))
+		netLogger.Info("scanning network")
+		netCtx := log.WithContext(ctx, netLogger)
+
+		discResult, err := discScanner.ScanNetwork(netCtx, network)
+		if err != nil {
+			return nil, fmt.Errorf("scan %s: %w", network, err)
+		}
+
+		discovery.ApplyContextRules(discResult, contextRules)
+
+		if deps.UploadDiscoveryResult != nil {
+			if err := deps.UploadDiscoveryResult(discResult); err != nil {
+				return nil, fmt.Errorf("upload results: %w", err)
+			}
+		}
+
+		result = map[string]interface{}{
+			"scanId":     discResult.ScanID,
+			"network":    network,
+			"hostsFound": discResult.Summary.OnlineHosts,
+			"byCategory": discResult.Summary.ByCategory,
+		}
+		netLogger.Info("hosts found", log.F("hosts_found", discResult.Summary.OnlineHosts))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/cloudmigrate/agent/internal/scanner"
+)
+
+// listHandler implements the LIST command: list files in a directory
+// non-recursively, up to a payload-supplied limit (default 100).
+type listHandler struct{}
+
+func (listHandler) Type() string { return "LIST" }
+
+func (listHandler) Validate(payload map[string]interface{}) error { return nil }
+
+func (listHandler) Execute(ctx context.Context, cmd Command, deps Deps) (map[string]interface{}, error) {
+	path := deps.BasePath
+	if p, ok := cmd.Payload["path"].(string); ok && p != "" {
+		path = p
+	}
+
+	limit := 100
+	if l, ok := cmd.Payload["limit"].(float64); ok {
+		limit = int(l)
+	}
+
+	files, err := scanner.ListFiles(path, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"files": files,
+		"count": len(files),
+	}, nil
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"sync"
+	"time"
+)
+
+// progressUpdateInterval and progressUpdatePct bound how often
+// commandProgress PATCHes the server: at most once per interval, or once
+// per that much forward movement, whichever comes first. Without a
+// throttle, a scan/discover of a large tree would issue one blocking
+// UpdateStatus call per file or host.
+const (
+	progressUpdateInterval = 2 * time.Second
+	progressUpdatePct      = 1.0
+)
+
+// commandProgress reports Add() calls as periodic RUNNING status updates
+// instead of drawing a terminal bar, since handlers run unattended inside
+// the daemon - the server-side UI renders the percentage instead. Updates
+// are coalesced rather than sent on every Add, since deps.UpdateStatus is
+// a blocking network call and scan/discover loops call Add once per
+// file/host.
+type commandProgress struct {
+	update func(cmdID, status string, result map[string]interface{}, errMsg string) error
+	cmdID  string
+
+	mu             sync.Mutex
+	total, current int64
+	lastSent       time.Time
+	lastPct        float64
+	sentOnce       bool
+}
+
+func (p *commandProgress) Start(total int64) {
+	p.mu.Lock()
+	p.total = total
+	p.mu.Unlock()
+}
+
+func (p *commandProgress) Add(n int64) {
+	p.mu.Lock()
+	p.current += n
+	pct := p.pctLocked()
+	now := time.Now()
+	due := !p.sentOnce || now.Sub(p.lastSent) >= progressUpdateInterval || pct-p.lastPct >= progressUpdatePct
+	if !due {
+		p.mu.Unlock()
+		return
+	}
+	p.sentOnce = true
+	p.lastSent = now
+	p.lastPct = pct
+	current, total := p.current, p.total
+	p.mu.Unlock()
+
+	p.update(p.cmdID, "RUNNING", map[string]interface{}{
+		"progress": pct,
+		"current":  current,
+		"total":    total,
+	}, "")
+}
+
+// Finish always sends a final update, regardless of the throttle, so the
+// last RUNNING status reflects the true end state rather than whatever
+// was last coalesced.
+func (p *commandProgress) Finish() {
+	p.mu.Lock()
+	current, total, pct := p.current, p.total, p.pctLocked()
+	p.mu.Unlock()
+
+	p.update(p.cmdID, "RUNNING", map[string]interface{}{
+		"progress": pct,
+		"current":  current,
+		"total":    total,
+	}, "")
+}
+
+func (p *commandProgress) pctLocked() float64 {
+	if p.total > 0 {
+		return float64(p.current) / float64(p.total) * 100
+	}
+	return 0
+}
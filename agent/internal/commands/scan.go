@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/cloudmigrate/agent/internal/log"
+	"github.com/cloudmigrate/agent/internal/scanner"
+)
+
+// scanHandler implements the SCAN command: scan a directory and upload the
+// result, reporting progress as RUNNING status updates.
+type scanHandler struct{}
+
+func (scanHandler) Type() string { return "SCAN" }
+
+func (scanHandler) Validate(payload map[string]interface{}) error { return nil }
+
+func (scanHandler) Execute(ctx context.Context, cmd Command, deps Deps) (map[string]interface{}, error) {
+	path := deps.BasePath
+	if p, ok := cmd.Payload["path"].(string); ok && p != "" {
+		path = p
+	}
+
+	scanOpts := scanner.DefaultScanOptions()
+	scanOpts.Progress = &commandProgress{update: deps.UpdateStatus, cmdID: cmd.ID}
+	scanOpts.Logger = deps.Logger.With(log.F("path", path))
+
+	result, err := scanner.ScanDirectoryWithOptions(path, scanOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if deps.UploadScanResult != nil {
+		deps.UploadScanResult(result)
+	}
+
+	return map[string]interface{}{
+		"fileCount":   result.FileCount,
+		"folderCount": result.FolderCount,
+		"totalSize":   result.TotalSize,
+	}, nil
+}
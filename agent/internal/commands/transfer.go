@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cloudmigrate/agent/internal/log"
+	"github.com/cloudmigrate/agent/internal/transfer"
+)
+
+// transferHandler implements the UPLOAD and DOWNLOAD commands: it builds a
+// Manager and Options from cmd.Payload, registers a cancel func so a
+// CANCEL command can interrupt it, and periodically pushes progress back
+// to the server as RUNNING status updates. Local paths are resolved
+// relative to deps.BasePath.
+type transferHandler struct {
+	upload bool
+}
+
+func (h transferHandler) Type() string {
+	if h.upload {
+		return "UPLOAD"
+	}
+	return "DOWNLOAD"
+}
+
+func (h transferHandler) Validate(payload map[string]interface{}) error {
+	bucket, _ := payload["bucket"].(string)
+	key, _ := payload["key"].(string)
+	if bucket == "" || key == "" {
+		return fmt.Errorf("bucket and key are required")
+	}
+	return nil
+}
+
+func (h transferHandler) Execute(ctx context.Context, cmd Command, deps Deps) (map[string]interface{}, error) {
+	opts, err := transferOptionsFromPayload(cmd.Payload, deps.BasePath)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := deps.Logger.With(log.F("bucket", opts.Bucket), log.F("key", opts.Key))
+
+	ctx, cancel := context.WithCancel(ctx)
+	ctx = log.WithContext(ctx, logger)
+	deps.RegisterTransfer(cmd.ID, cancel)
+	defer func() {
+		cancel()
+		deps.UnregisterTransfer(cmd.ID)
+	}()
+
+	mgr, err := deps.NewTransferManager(ctx, opts.Endpoint, opts.Region, filepath.Join(deps.BasePath, ".cloudmigrate", "transfers"))
+	if err != nil {
+		return nil, fmt.Errorf("set up transfer manager: %w", err)
+	}
+
+	onProgress := func(p transfer.Progress) {
+		deps.UpdateStatus(cmd.ID, "RUNNING", map[string]interface{}{
+			"bytesTransferred": p.BytesTransferred,
+			"totalBytes":       p.TotalBytes,
+			"throughputBps":    p.ThroughputBps,
+			"etaSeconds":       p.ETA.Seconds(),
+		}, "")
+	}
+
+	transferFn := mgr.Download
+	verb := "Downloading"
+	if h.upload {
+		transferFn = mgr.Upload
+		verb = "Uploading"
+	}
+	logger.Info(verb, log.F("path", opts.Path))
+
+	if err := transferFn(ctx, opts, onProgress); err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.New("transfer cancelled")
+		}
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"bucket": opts.Bucket,
+		"key":    opts.Key,
+		"path":   opts.Path,
+	}, nil
+}
+
+// transferOptionsFromPayload reads UPLOAD/DOWNLOAD command fields
+// ("bucket", "key", "path", "partSize", "concurrency", "resume",
+// "endpoint", "region") into transfer.Options. path, if relative, is
+// resolved against basePath; bucket and key are required.
+func transferOptionsFromPayload(payload map[string]interface{}, basePath string) (transfer.Options, error) {
+	var opts transfer.Options
+
+	bucket, _ := payload["bucket"].(string)
+	key, _ := payload["key"].(string)
+	if bucket == "" || key == "" {
+		return opts, fmt.Errorf("bucket and key are required")
+	}
+	opts.Bucket = bucket
+	opts.Key = key
+
+	path, _ := payload["path"].(string)
+	if path == "" {
+		path = filepath.Join(basePath, filepath.Base(key))
+	} else if !filepath.IsAbs(path) {
+		path = filepath.Join(basePath, path)
+	}
+	opts.Path = path
+
+	if v, ok := payload["partSize"].(float64); ok && v > 0 {
+		opts.PartSize = int64(v)
+	}
+	if v, ok := payload["concurrency"].(float64); ok && v > 0 {
+		opts.Concurrency = int(v)
+	}
+	if v, ok := payload["resume"].(bool); ok {
+		opts.Resume = v
+	}
+	if v, ok := payload["endpoint"].(string); ok {
+		opts.Endpoint = v
+	}
+	if v, ok := payload["region"].(string); ok {
+		opts.Region = v
+	}
+
+	return opts, nil
+}
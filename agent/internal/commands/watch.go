@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudmigrate/agent/internal/discovery"
+	"github.com/cloudmigrate/agent/internal/log"
+)
+
+// watchNetworkHandler implements the WATCH_NETWORK command: it takes a
+// baseline discovery scan of payload["network"], then feeds targeted
+// rescans through Scanner.Watch as change events arrive, uploading each
+// delta as it's produced. It runs until cancelled (daemon shutdown or a
+// CANCEL command), so it's dispatched on its own goroutine by
+// Client.RunDaemon the same way UPLOAD/DOWNLOAD are.
+type watchNetworkHandler struct{}
+
+func (watchNetworkHandler) Type() string { return "WATCH_NETWORK" }
+
+func (watchNetworkHandler) Validate(payload map[string]interface{}) error {
+	network, _ := payload["network"].(string)
+	if network == "" {
+		return fmt.Errorf("WATCH_NETWORK command missing network")
+	}
+	return nil
+}
+
+func (watchNetworkHandler) Execute(ctx context.Context, cmd Command, deps Deps) (map[string]interface{}, error) {
+	network := cmd.Payload["network"].(string)
+
+	ctx, cancel := context.WithCancel(ctx)
+	deps.RegisterTransfer(cmd.ID, cancel)
+	defer func() {
+		cancel()
+		deps.UnregisterTransfer(cmd.ID)
+	}()
+
+	opts := discovery.DefaultScanOptions()
+	discScanner := discovery.NewScanner(opts)
+
+	deps.Logger.Info("watch: taking baseline scan", log.F("network", network))
+	baseline, err := discScanner.ScanNetwork(ctx, network)
+	if err != nil {
+		return nil, fmt.Errorf("baseline scan %s: %w", network, err)
+	}
+	if deps.UploadDiscoveryResult != nil {
+		if err := deps.UploadDiscoveryResult(baseline); err != nil {
+			deps.Logger.Warn("failed to upload baseline scan", log.F("error", err.Error()))
+		}
+	}
+
+	source, err := eventSourceFromPayload(cmd.Payload)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	debounce := 5 * time.Second
+	if secs, ok := cmd.Payload["debounceSec"].(float64); ok && secs > 0 {
+		debounce = time.Duration(secs * float64(time.Second))
+	}
+
+	deltas, err := discScanner.Watch(ctx, baseline, source, debounce)
+	if err != nil {
+		return nil, fmt.Errorf("start watch: %w", err)
+	}
+
+	deltaCount := 0
+	for delta := range deltas {
+		deltaCount++
+		if deps.UploadDiscoveryResult != nil {
+			if err := deps.UploadDiscoveryResult(delta); err != nil {
+				deps.Logger.Warn("failed to upload watch delta", log.F("error", err.Error()))
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"network": network,
+		"deltas":  deltaCount,
+	}, nil
+}
+
+// eventSourceFromPayload builds the EventSource a WATCH_NETWORK command
+// should use: a file tail when payload["tailFile"] is set, an AMQP feed
+// when payload["amqpUrl"] is set, or a Noop source (the rescan machinery
+// runs, but nothing triggers it) otherwise.
+func eventSourceFromPayload(payload map[string]interface{}) (discovery.EventSource, error) {
+	if path, ok := payload["tailFile"].(string); ok && path != "" {
+		interval := time.Second
+		if secs, ok := payload["tailPollSec"].(float64); ok && secs > 0 {
+			interval = time.Duration(secs * float64(time.Second))
+		}
+		return discovery.NewFileTailEventSource(path, interval)
+	}
+
+	if url, ok := payload["amqpUrl"].(string); ok && url != "" {
+		exchange, _ := payload["amqpExchange"].(string)
+		routingKey, _ := payload["amqpRoutingKey"].(string)
+		if exchange == "" {
+			exchange = "cloudmigrate.infra-events"
+		}
+		return discovery.NewAMQPEventSource(url, exchange, routingKey)
+	}
+
+	return discovery.NewNoopEventSource(), nil
+}
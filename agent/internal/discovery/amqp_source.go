@@ -0,0 +1,111 @@
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// amqpPayload is the expected JSON body of an infrastructure-change
+// message: {"ip": "10.0.0.5"} or {"cidr": "10.0.0.0/24"}.
+type amqpPayload struct {
+	IP   string `json:"ip"`
+	CIDR string `json:"cidr"`
+}
+
+// AMQPEventSource consumes infrastructure-change events from an AMQP topic
+// exchange (RabbitMQ or compatible) and feeds them to Scanner.Watch.
+type AMQPEventSource struct {
+	conn    *amqp.Connection
+	ch      *amqp.Channel
+	events  chan Event
+	closeCh chan struct{}
+}
+
+// NewAMQPEventSource connects to url, declares a topic exchange named
+// exchange (if it doesn't already exist), binds an exclusive queue to it
+// with routingKey, and starts consuming.
+func NewAMQPEventSource(url, exchange, routingKey string) (*AMQPEventSource, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("amqp dial: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("amqp channel: %w", err)
+	}
+
+	if err := ch.ExchangeDeclare(exchange, "topic", true, false, false, false, nil); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("declare exchange %s: %w", exchange, err)
+	}
+
+	q, err := ch.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("declare queue: %w", err)
+	}
+
+	if err := ch.QueueBind(q.Name, routingKey, exchange, false, nil); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("bind queue: %w", err)
+	}
+
+	deliveries, err := ch.Consume(q.Name, "", true, true, false, false, nil)
+	if err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("consume: %w", err)
+	}
+
+	src := &AMQPEventSource{
+		conn:    conn,
+		ch:      ch,
+		events:  make(chan Event, 64),
+		closeCh: make(chan struct{}),
+	}
+
+	go src.run(deliveries)
+	return src, nil
+}
+
+func (s *AMQPEventSource) run(deliveries <-chan amqp.Delivery) {
+	defer close(s.events)
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case d, ok := <-deliveries:
+			if !ok {
+				return
+			}
+			var payload amqpPayload
+			if err := json.Unmarshal(d.Body, &payload); err != nil {
+				continue // malformed message - drop and keep consuming
+			}
+			ev := Event{IP: payload.IP, CIDR: payload.CIDR}
+			if ev.IP == "" && ev.CIDR == "" {
+				continue
+			}
+			select {
+			case s.events <- ev:
+			case <-s.closeCh:
+				return
+			}
+		}
+	}
+}
+
+func (s *AMQPEventSource) Events() <-chan Event { return s.events }
+
+func (s *AMQPEventSource) Close() error {
+	close(s.closeCh)
+	s.ch.Close()
+	return s.conn.Close()
+}
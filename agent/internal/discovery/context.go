@@ -0,0 +1,36 @@
+package discovery
+
+import "github.com/cloudmigrate/agent/internal/discovery/enrich"
+
+// ApplyContextRules labels every host in result according to rules. It is
+// meant to run after ScanNetwork and before the result is printed or
+// uploaded, so labels show up in both places.
+func ApplyContextRules(result *DiscoveryResult, rules []enrich.Rule) {
+	if len(rules) == 0 {
+		return
+	}
+
+	for i := range result.Hosts {
+		result.Hosts[i].Labels = enrich.Apply(rules, toEnrichHost(result.Hosts[i]))
+	}
+}
+
+func toEnrichHost(host DiscoveredHost) enrich.Host {
+	ports := make([]int, len(host.OpenPorts))
+	for i, p := range host.OpenPorts {
+		ports[i] = p.Port
+	}
+
+	services := make([]string, len(host.Services))
+	for i, s := range host.Services {
+		services[i] = s.Name
+	}
+
+	return enrich.Host{
+		IP:       host.IP,
+		Hostname: host.Hostname,
+		OS:       host.OS,
+		Services: services,
+		Ports:    ports,
+	}
+}
@@ -0,0 +1,92 @@
+package detectors
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var versionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(\d+\.\d+\.\d+)`),
+	regexp.MustCompile(`(\d+\.\d+)`),
+	regexp.MustCompile(`(?i)version[:\s]+(\S+)`),
+	regexp.MustCompile(`(?i)ver[:\s]+(\S+)`),
+}
+
+// extractVersion pulls the first version-looking token out of a banner.
+func extractVersion(banner string) string {
+	for _, re := range versionPatterns {
+		if m := re.FindStringSubmatch(banner); len(m) > 1 {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// BannerDetector is a generic fallback: it grabs whatever the service says
+// first and reports a match (only when the banner satisfies matchPattern, if
+// one is set) at confidence 80 when a banner was read and 60 when none was. This is
+// what covers the many signatures that don't warrant a bespoke
+// protocol handshake (yet), and what the YAML/JSON custom detector loader
+// builds on behalf of operators.
+type BannerDetector struct {
+	name      string
+	ports     []int
+	category  string
+	awsTarget string
+	// matchPattern, if set, must match the banner for Probe to report a hit;
+	// an empty pattern means "any response counts".
+	matchPattern *regexp.Regexp
+	readTimeout  time.Duration
+}
+
+// NewBannerDetector builds a BannerDetector. matchRegex may be empty.
+func NewBannerDetector(name string, ports []int, category, awsTarget, matchRegex string) (*BannerDetector, error) {
+	d := &BannerDetector{
+		name:        name,
+		ports:       ports,
+		category:    category,
+		awsTarget:   awsTarget,
+		readTimeout: 2 * time.Second,
+	}
+	if matchRegex != "" {
+		re, err := regexp.Compile(matchRegex)
+		if err != nil {
+			return nil, err
+		}
+		d.matchPattern = re
+	}
+	return d, nil
+}
+
+func (d *BannerDetector) Name() string      { return d.name }
+func (d *BannerDetector) Ports() []int      { return d.ports }
+func (d *BannerDetector) Category() string  { return d.category }
+func (d *BannerDetector) AWSTarget() string { return d.awsTarget }
+
+func (d *BannerDetector) Probe(ctx context.Context, conn net.Conn) (*Result, error) {
+	conn.SetReadDeadline(time.Now().Add(d.readTimeout))
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	banner := ""
+	if err == nil && n > 0 {
+		banner = strings.TrimSpace(string(buf[:n]))
+	}
+
+	if d.matchPattern != nil && !d.matchPattern.MatchString(banner) {
+		return nil, nil
+	}
+
+	confidence := 60
+	if banner != "" {
+		confidence = 80
+	}
+
+	return &Result{
+		Name:       d.name,
+		Version:    extractVersion(banner),
+		Confidence: confidence,
+	}, nil
+}
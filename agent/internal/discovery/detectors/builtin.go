@@ -0,0 +1,80 @@
+package detectors
+
+// builtinSignature describes one entry of the old static serviceSignatures
+// map, now re-expressed as a generic BannerDetector registration. These
+// cover protocols distinctive enough to identify by name/port alone, where a
+// bespoke handshake (like mysql.go or postgres.go) isn't worth the code.
+type builtinSignature struct {
+	name      string
+	ports     []int
+	category  string
+	awsTarget string
+}
+
+// builtinSignatures mirrors the original 12-category port map. Protocols
+// with their own Detector (SSH, HTTP, HTTPS, MySQL, PostgreSQL, Redis, SMB)
+// are intentionally left out here.
+var builtinSignatures = []builtinSignature{
+	// 1. COMPUTE
+	{"RDP", []int{3389}, "compute", "EC2 Windows"},
+	{"VNC", []int{5900}, "compute", "EC2"},
+
+	// 2. DATABASES
+	{"SQL Server", []int{1433}, "databases", "RDS SQL Server"},
+	{"Oracle", []int{1521}, "databases", "RDS Oracle"},
+	{"MongoDB", []int{27017}, "databases", "DocumentDB"},
+
+	// 3. FILE STORAGE
+	{"NetBIOS", []int{139}, "storage", "FSx"},
+	{"NFS", []int{2049}, "storage", "EFS"},
+	{"FTP", []int{21}, "storage", "S3/Transfer Family"},
+
+	// 4. IDENTITY
+	{"LDAP", []int{389}, "identity", "Managed AD"},
+	{"LDAPS", []int{636}, "identity", "Managed AD"},
+	{"Kerberos", []int{88}, "identity", "Managed AD"},
+	{"Kerberos Password", []int{464}, "identity", "Managed AD"},
+
+	// 5. NETWORKS & VPN
+	{"IKE/IPSec", []int{500}, "networking", "VPN"},
+	{"IPSec NAT-T", []int{4500}, "networking", "VPN"},
+	{"OpenVPN", []int{1194}, "networking", "VPN"},
+
+	// 7. BACKUPS / DR
+	{"Veeam", []int{9392}, "backups", "AWS Backup"},
+	{"Veeam Data Mover", []int{10006}, "backups", "S3 Glacier"},
+
+	// 8. LOGGING / MONITORING
+	{"Prometheus", []int{9090}, "monitoring", "Managed Prometheus"},
+	{"Node Exporter", []int{9100}, "monitoring", "CloudWatch"},
+	{"Elasticsearch", []int{9200}, "monitoring", "OpenSearch"},
+	{"Kibana", []int{5601}, "monitoring", "OpenSearch"},
+	{"Syslog", []int{514}, "monitoring", "CloudWatch"},
+
+	// 9. DEVOPS PIPELINES
+	{"GitLab", []int{8929}, "devops", "CodePipeline"},
+	{"Bitbucket", []int{7990}, "devops", "CodeCommit"},
+
+	// 10. MESSAGING & QUEUES
+	{"RabbitMQ", []int{5672}, "messaging", "SQS/MQ"},
+	{"Kafka", []int{9092}, "messaging", "MSK"},
+	{"ActiveMQ", []int{61616}, "messaging", "MQ"},
+
+	// 11. EMAIL / SMTP
+	{"SMTP", []int{25}, "email", "SES"},
+	{"SMTP Submission", []int{587}, "email", "SES"},
+	{"IMAPS", []int{993}, "email", "WorkMail"},
+	{"POP3S", []int{995}, "email", "WorkMail"},
+}
+
+func init() {
+	for _, sig := range builtinSignatures {
+		d, err := NewBannerDetector(sig.name, sig.ports, sig.category, sig.awsTarget, "")
+		if err != nil {
+			// matchRegex is always empty here, so Compile can't fail; guard
+			// anyway rather than ignoring the error silently.
+			panic(err)
+		}
+		Register(d)
+	}
+}
@@ -0,0 +1,61 @@
+package detectors
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// customDetectorConfig is the on-disk shape of detectors.yaml: a list of
+// BannerDetector definitions an operator can add without a code change.
+type customDetectorConfig struct {
+	Detectors []customDetector `yaml:"detectors"`
+}
+
+type customDetector struct {
+	Name      string `yaml:"name"`
+	Ports     []int  `yaml:"ports"`
+	Category  string `yaml:"category"`
+	AWSTarget string `yaml:"awsTarget"`
+	Match     string `yaml:"match,omitempty"` // optional regex the banner must satisfy
+}
+
+// DefaultConfigPath returns the conventional location of detectors.yaml,
+// kept alongside the agent's other local state.
+func DefaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".cloudmigrate", "detectors.yaml")
+	}
+	return filepath.Join(home, ".cloudmigrate", "detectors.yaml")
+}
+
+// LoadCustomDetectors reads detectors.yaml at path and registers a
+// BannerDetector for each entry. A missing file is not an error: callers
+// should treat it as "no custom detectors configured".
+func LoadCustomDetectors(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read detectors file: %w", err)
+	}
+
+	var cfg customDetectorConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("parse detectors file: %w", err)
+	}
+
+	for _, cd := range cfg.Detectors {
+		d, err := NewBannerDetector(cd.Name, cd.Ports, cd.Category, cd.AWSTarget, cd.Match)
+		if err != nil {
+			return fmt.Errorf("detector %q: %w", cd.Name, err)
+		}
+		Register(d)
+	}
+
+	return nil
+}
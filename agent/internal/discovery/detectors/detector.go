@@ -0,0 +1,108 @@
+// Package detectors implements a pluggable registry of service detectors,
+// modeled on input-plugin registries like telegraf's: each protocol gets its
+// own small Detector instead of the port scanner baking in a closed set of
+// signatures. scanPort probes every detector registered against the port it
+// just found open and keeps the highest-confidence match.
+package detectors
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+)
+
+// Result is what a Detector reports about a service it recognized.
+type Result struct {
+	Name       string
+	Product    string
+	Version    string
+	Category   string
+	AWSTarget  string
+	Confidence int // 0-100, higher wins when multiple detectors match a port
+}
+
+// Detector recognizes one service/protocol. Probe is given an already-open
+// connection (scanPort has already confirmed the port is listening) and
+// should do whatever protocol-specific handshake is needed - an HTTP GET, a
+// MySQL greeting parse, a Redis PING, a TLS ClientHello, etc. - rather than
+// relying on a generic banner grab.
+type Detector interface {
+	Name() string
+	Ports() []int
+	Category() string
+	AWSTarget() string
+	Probe(ctx context.Context, conn net.Conn) (*Result, error)
+}
+
+var registry = struct {
+	mu     sync.Mutex
+	byPort map[int][]Detector
+	all    []Detector
+}{byPort: make(map[int][]Detector)}
+
+// Register adds d to the registry for every port it declares. Safe to call
+// from package init() functions.
+func Register(d Detector) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.all = append(registry.all, d)
+	for _, port := range d.Ports() {
+		registry.byPort[port] = append(registry.byPort[port], d)
+	}
+}
+
+// ForPort returns every detector registered for port, in registration order.
+func ForPort(port int) []Detector {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	out := make([]Detector, len(registry.byPort[port]))
+	copy(out, registry.byPort[port])
+	return out
+}
+
+// All returns every registered detector, sorted by name for deterministic
+// iteration (config listings, diagnostics).
+func All() []Detector {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	out := make([]Detector, len(registry.all))
+	copy(out, registry.all)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out
+}
+
+// Probe runs every detector registered for port and returns the
+// highest-confidence match, or nil if none of them recognized the service.
+// Detector.Probe implementations read from (and some, like the TLS
+// detector, close) the connection they're given, so a fresh connection is
+// dialed per detector rather than sharing one across every candidate - a
+// second detector handed an already-drained or closed connection would
+// never see its handshake.
+func Probe(ctx context.Context, port int, dial func() (net.Conn, error)) *Result {
+	var best *Result
+	for _, d := range ForPort(port) {
+		conn, err := dial()
+		if err != nil {
+			continue
+		}
+		res, err := d.Probe(ctx, conn)
+		conn.Close()
+		if err != nil || res == nil {
+			continue
+		}
+		if res.Category == "" {
+			res.Category = d.Category()
+		}
+		if res.AWSTarget == "" {
+			res.AWSTarget = d.AWSTarget()
+		}
+		if best == nil || res.Confidence > best.Confidence {
+			best = res
+		}
+	}
+	return best
+}
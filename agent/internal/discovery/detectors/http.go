@@ -0,0 +1,55 @@
+package detectors
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+func init() {
+	Register(&httpDetector{})
+}
+
+// httpDetector issues a GET / and reads the Server header, which is the
+// most reliable way to tell Apache/Nginx/IIS/Jenkins apart from a generic
+// banner grab.
+type httpDetector struct{}
+
+func (httpDetector) Name() string      { return "HTTP" }
+func (httpDetector) Ports() []int      { return []int{80, 8080, 8000, 3000, 5000, 8081, 9000} }
+func (httpDetector) Category() string  { return "webapps" }
+func (httpDetector) AWSTarget() string { return "EC2/ECS/ALB" }
+
+func (httpDetector) Probe(ctx context.Context, conn net.Conn) (*Result, error) {
+	conn.SetDeadline(time.Now().Add(3 * time.Second))
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "cloudmigrate-agent-discovery")
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	server := resp.Header.Get("Server")
+	confidence := 70
+	if server != "" {
+		confidence = 90
+	}
+
+	return &Result{
+		Name:       "HTTP",
+		Product:    server,
+		Version:    extractVersion(server),
+		Confidence: confidence,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package detectors
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(&mysqlDetector{})
+}
+
+// mysqlDetector reads the initial handshake packet MySQL/MariaDB servers
+// send unprompted: a 4-byte packet header, a protocol version byte, then a
+// NUL-terminated server version string, e.g. "8.0.35" or
+// "10.11.2-MariaDB-1ubuntu1".
+type mysqlDetector struct{}
+
+func (mysqlDetector) Name() string      { return "MySQL" }
+func (mysqlDetector) Ports() []int      { return []int{3306} }
+func (mysqlDetector) Category() string  { return "databases" }
+func (mysqlDetector) AWSTarget() string { return "RDS" }
+
+func (mysqlDetector) Probe(ctx context.Context, conn net.Conn) (*Result, error) {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	buf := make([]byte, 256)
+	n, err := conn.Read(buf)
+	if err != nil || n < 6 {
+		return nil, err
+	}
+	buf = buf[:n]
+
+	// buf[0:3] = payload length, buf[3] = sequence id, buf[4] = protocol version
+	if buf[4] != 0x0a {
+		return nil, nil
+	}
+
+	end := 5
+	for end < len(buf) && buf[end] != 0x00 {
+		end++
+	}
+	version := string(buf[5:end])
+
+	product := "MySQL"
+	if strings.Contains(strings.ToLower(version), "mariadb") {
+		product = "MariaDB"
+	}
+
+	return &Result{
+		Name:       product,
+		Product:    product,
+		Version:    version,
+		Confidence: 95,
+	}, nil
+}
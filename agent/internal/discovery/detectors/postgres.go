@@ -0,0 +1,53 @@
+package detectors
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"time"
+)
+
+func init() {
+	Register(&postgresDetector{})
+}
+
+// postgresDetector sends an SSLRequest (request code 1234.5679, one above the
+// "cancel request" code 1234.5678) and checks the one-byte reply: 'S', 'N',
+// or the 'E' of an ErrorResponse - real Postgres is the only thing on 5432
+// that speaks this wire format at all.
+type postgresDetector struct{}
+
+func (postgresDetector) Name() string      { return "PostgreSQL" }
+func (postgresDetector) Ports() []int      { return []int{5432} }
+func (postgresDetector) Category() string  { return "databases" }
+func (postgresDetector) AWSTarget() string { return "RDS" }
+
+func (postgresDetector) Probe(ctx context.Context, conn net.Conn) (*Result, error) {
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	// SSLRequest: length(4) + request code(4) = 8 bytes total.
+	req := make([]byte, 8)
+	binary.BigEndian.PutUint32(req[0:4], 8)
+	binary.BigEndian.PutUint32(req[4:8], 80877103)
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	resp := make([]byte, 1)
+	n, err := conn.Read(resp)
+	if err != nil || n < 1 {
+		return nil, err
+	}
+
+	// Real Postgres answers 'S' (will do SSL), 'N' (plaintext only), or an
+	// ErrorResponse starting with 'E'. Anything else isn't Postgres.
+	switch resp[0] {
+	case 'S', 'N', 'E':
+		return &Result{
+			Name:       "PostgreSQL",
+			Confidence: 90,
+		}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,45 @@
+package detectors
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(&redisDetector{})
+}
+
+// redisDetector sends a RESP-encoded PING and expects back "+PONG" (or a
+// NOAUTH error, which still confirms Redis/Valkey is listening).
+type redisDetector struct{}
+
+func (redisDetector) Name() string      { return "Redis" }
+func (redisDetector) Ports() []int      { return []int{6379} }
+func (redisDetector) Category() string  { return "databases" }
+func (redisDetector) AWSTarget() string { return "ElastiCache" }
+
+func (redisDetector) Probe(ctx context.Context, conn net.Conn) (*Result, error) {
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if _, err := conn.Write([]byte("*1\r\n$4\r\nPING\r\n")); err != nil {
+		return nil, err
+	}
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSpace(line)
+
+	switch {
+	case strings.EqualFold(line, "+PONG"):
+		return &Result{Name: "Redis", Confidence: 95}, nil
+	case strings.HasPrefix(line, "-NOAUTH"):
+		return &Result{Name: "Redis", Product: "auth required", Confidence: 90}, nil
+	default:
+		return nil, nil
+	}
+}
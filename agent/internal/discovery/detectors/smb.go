@@ -0,0 +1,61 @@
+package detectors
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+func init() {
+	Register(&smbDetector{})
+}
+
+// smbDetector sends an SMB1 negotiate-protocol request (the dialect that
+// every SMB implementation, from Samba to Windows, still answers to even
+// when it immediately negotiates up to SMB2/3) and checks for the "\xffSMB"
+// signature in the response.
+type smbDetector struct{}
+
+func (smbDetector) Name() string      { return "SMB" }
+func (smbDetector) Ports() []int      { return []int{445} }
+func (smbDetector) Category() string  { return "storage" }
+func (smbDetector) AWSTarget() string { return "FSx" }
+
+var smbNegotiateRequest = []byte{
+	0x00, 0x00, 0x00, 0x2f, // NetBIOS session header, length
+	0xff, 0x53, 0x4d, 0x42, // "\xffSMB"
+	0x72,                   // command: negotiate protocol
+	0x00, 0x00, 0x00, 0x00, // status
+	0x18,       // flags
+	0x01, 0x28, // flags2
+	0x00, 0x00, // process id high
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // signature
+	0x00, 0x00, // reserved
+	0xff, 0xff, // tree id
+	0x00, 0x00, // process id
+	0x00, 0x00, // user id
+	0x00, 0x00, // multiplex id
+	0x00,       // word count
+	0x0c, 0x00, // byte count
+	0x02, // dialect buffer format
+	'N', 'T', ' ', 'L', 'M', ' ', '0', '.', '1', '2', 0x00,
+}
+
+func (smbDetector) Probe(ctx context.Context, conn net.Conn) (*Result, error) {
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if _, err := conn.Write(smbNegotiateRequest); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 256)
+	n, err := conn.Read(buf)
+	if err != nil || n < 8 {
+		return nil, err
+	}
+
+	if buf[4] == 0xff && buf[5] == 'S' && buf[6] == 'M' && buf[7] == 'B' {
+		return &Result{Name: "SMB", Confidence: 90}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,49 @@
+package detectors
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(&sshDetector{})
+}
+
+// sshDetector reads the SSH identification string every compliant server
+// sends immediately on connect (RFC 4253 §4.2), e.g.
+// "SSH-2.0-OpenSSH_8.9p1 Ubuntu-3ubuntu0.4".
+type sshDetector struct{}
+
+func (sshDetector) Name() string      { return "SSH" }
+func (sshDetector) Ports() []int      { return []int{22} }
+func (sshDetector) Category() string  { return "compute" }
+func (sshDetector) AWSTarget() string { return "EC2" }
+
+func (sshDetector) Probe(ctx context.Context, conn net.Conn) (*Result, error) {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "SSH-") {
+		return nil, nil
+	}
+
+	product := ""
+	version := ""
+	if parts := strings.SplitN(line, "-", 3); len(parts) == 3 {
+		product = parts[2]
+		version = extractVersion(product)
+	}
+
+	return &Result{
+		Name:       "SSH",
+		Product:    product,
+		Version:    version,
+		Confidence: 95,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package detectors
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+func init() {
+	Register(&tlsDetector{})
+}
+
+// tlsDetector completes a TLS handshake (ClientHello offering ALPN "h2" and
+// "http/1.1") and reports the service as HTTPS, with the leaf certificate's
+// CN as the product. The negotiated ALPN protocol and the certificate's SANs
+// are not inspected, so other TLS-wrapped protocols on these ports are also
+// reported as HTTPS.
+type tlsDetector struct{}
+
+func (tlsDetector) Name() string      { return "TLS" }
+func (tlsDetector) Ports() []int      { return []int{443, 8443} }
+func (tlsDetector) Category() string  { return "webapps" }
+func (tlsDetector) AWSTarget() string { return "EC2/ECS/ALB" }
+
+func (tlsDetector) Probe(ctx context.Context, conn net.Conn) (*Result, error) {
+	conn.SetDeadline(time.Now().Add(3 * time.Second))
+
+	client := tls.Client(conn, &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"h2", "http/1.1"},
+	})
+	defer client.Close()
+
+	if err := client.HandshakeContext(ctx); err != nil {
+		return nil, err
+	}
+
+	state := client.ConnectionState()
+	product := "TLS"
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		if cert.Subject.CommonName != "" {
+			product = cert.Subject.CommonName
+		}
+	}
+
+	return &Result{
+		Name:       "HTTPS",
+		Product:    product,
+		Confidence: 85,
+	}, nil
+}
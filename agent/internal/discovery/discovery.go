@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"net"
 	"os/exec"
-	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/cloudmigrate/agent/internal/discovery/detectors"
+	"github.com/cloudmigrate/agent/internal/discovery/inspect"
+	"github.com/cloudmigrate/agent/internal/discovery/vuln"
+	"github.com/cloudmigrate/agent/internal/log"
+	"github.com/cloudmigrate/agent/internal/progress"
 )
 
 // DiscoveryResult contains all discovered infrastructure
@@ -25,27 +30,40 @@ type DiscoveryResult struct {
 
 // DiscoverySummary provides counts by category
 type DiscoverySummary struct {
-	TotalHosts   int            `json:"totalHosts"`
-	OnlineHosts  int            `json:"onlineHosts"`
-	ByCategory   map[string]int `json:"byCategory"`
-	ByOS         map[string]int `json:"byOs"`
+	TotalHosts    int            `json:"totalHosts"`
+	OnlineHosts   int            `json:"onlineHosts"`
+	ByCategory    map[string]int `json:"byCategory"`
+	ByOS          map[string]int `json:"byOs"`
+	HighRiskHosts int            `json:"highRiskHosts"`
 }
 
 // DiscoveredHost represents a discovered network host
 type DiscoveredHost struct {
-	IP           string            `json:"ip"`
-	Hostname     string            `json:"hostname"`
-	MAC          string            `json:"mac,omitempty"`
-	Status       string            `json:"status"` // online, offline
-	OS           string            `json:"os,omitempty"`
-	OSVersion    string            `json:"osVersion,omitempty"`
-	OpenPorts    []PortInfo        `json:"openPorts"`
-	Services     []ServiceInfo     `json:"services"`
-	Category     string            `json:"category"` // compute, database, storage, etc.
-	AWSTarget    string            `json:"awsTarget,omitempty"`
-	ResponseTime int64             `json:"responseTimeMs"`
-	LastSeen     time.Time         `json:"lastSeen"`
-	Metadata     map[string]string `json:"metadata,omitempty"`
+	IP            string            `json:"ip"`
+	Hostname      string            `json:"hostname"`
+	MAC           string            `json:"mac,omitempty"`
+	Status        string            `json:"status"` // online, offline
+	OS            string            `json:"os,omitempty"`
+	OSVersion     string            `json:"osVersion,omitempty"`
+	OpenPorts     []PortInfo        `json:"openPorts"`
+	Services      []ServiceInfo     `json:"services"`
+	Category      string            `json:"category"` // compute, database, storage, etc.
+	AWSTarget     string            `json:"awsTarget,omitempty"`
+	ResponseTime  int64             `json:"responseTimeMs"`
+	LastSeen      time.Time         `json:"lastSeen"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	ScheduledJobs []JobInfo         `json:"scheduledJobs,omitempty"`
+	RiskScore     int               `json:"riskScore"` // 0-100, rolled up from Services' CVEs/EndOfLife
+}
+
+// JobInfo describes a scheduled job (cron entry, systemd timer, ...) found
+// by the SSH-based Inspector.
+type JobInfo struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule,omitempty"`
+	Command  string `json:"command"`
+	Source   string `json:"source"` // crontab, cron.d, systemd-timer
 }
 
 // PortInfo represents an open port
@@ -59,13 +77,15 @@ type PortInfo struct {
 
 // ServiceInfo represents a detected service
 type ServiceInfo struct {
-	Name        string `json:"name"`
-	Type        string `json:"type"` // database, web, mail, etc.
-	Version     string `json:"version,omitempty"`
-	Port        int    `json:"port"`
-	Product     string `json:"product,omitempty"`
-	AWSTarget   string `json:"awsTarget"`
-	Confidence  int    `json:"confidence"` // 0-100
+	Name       string          `json:"name"`
+	Type       string          `json:"type"` // database, web, mail, etc.
+	Version    string          `json:"version,omitempty"`
+	Port       int             `json:"port"`
+	Product    string          `json:"product,omitempty"`
+	AWSTarget  string          `json:"awsTarget"`
+	Confidence int             `json:"confidence"` // 0-100
+	CVEs       []vuln.CVEMatch `json:"cves,omitempty"`
+	EndOfLife  *time.Time      `json:"endOfLife,omitempty"`
 }
 
 // Common ports to scan
@@ -92,95 +112,45 @@ var commonPorts = []int{
 	8081, 9000, 9200, 9300,
 }
 
-// ServiceSignatures maps ports to services - THE 12 CATEGORIES THAT MATTER
-var serviceSignatures = map[int]ServiceInfo{
-	// 1. COMPUTE - VMs, physical servers
-	22:   {Name: "SSH", Type: "compute", AWSTarget: "EC2"},
-	3389: {Name: "RDP", Type: "compute", AWSTarget: "EC2 Windows"},
-	5900: {Name: "VNC", Type: "compute", AWSTarget: "EC2"},
-
-	// 2. DATABASES - SQL Server, Oracle, MySQL, PostgreSQL
-	3306:  {Name: "MySQL", Type: "databases", AWSTarget: "RDS MySQL"},
-	5432:  {Name: "PostgreSQL", Type: "databases", AWSTarget: "RDS PostgreSQL"},
-	1433:  {Name: "SQL Server", Type: "databases", AWSTarget: "RDS SQL Server"},
-	1521:  {Name: "Oracle", Type: "databases", AWSTarget: "RDS Oracle"},
-	27017: {Name: "MongoDB", Type: "databases", AWSTarget: "DocumentDB"},
-	6379:  {Name: "Redis", Type: "databases", AWSTarget: "ElastiCache"},
-
-	// 3. FILE STORAGE - SMB shares, NAS, file servers
-	445:  {Name: "SMB", Type: "storage", AWSTarget: "FSx"},
-	139:  {Name: "NetBIOS", Type: "storage", AWSTarget: "FSx"},
-	2049: {Name: "NFS", Type: "storage", AWSTarget: "EFS"},
-	21:   {Name: "FTP", Type: "storage", AWSTarget: "S3/Transfer Family"},
-
-	// 4. IDENTITY - Active Directory, LDAP
-	389: {Name: "LDAP", Type: "identity", AWSTarget: "Managed AD"},
-	636: {Name: "LDAPS", Type: "identity", AWSTarget: "Managed AD"},
-	88:  {Name: "Kerberos", Type: "identity", AWSTarget: "Managed AD"},
-	464: {Name: "Kerberos Password", Type: "identity", AWSTarget: "Managed AD"},
-
-	// 5. NETWORKS & VPN - Firewalls, routers, VPN
-	500:  {Name: "IKE/IPSec", Type: "networking", AWSTarget: "VPN"},
-	4500: {Name: "IPSec NAT-T", Type: "networking", AWSTarget: "VPN"},
-	1194: {Name: "OpenVPN", Type: "networking", AWSTarget: "VPN"},
-
-	// 6. WEB APPS - IIS, Apache, Nginx
-	80:   {Name: "HTTP", Type: "webapps", AWSTarget: "EC2/ECS/ALB"},
-	443:  {Name: "HTTPS", Type: "webapps", AWSTarget: "EC2/ECS/CloudFront"},
-	8080: {Name: "HTTP Alt", Type: "webapps", AWSTarget: "EC2/ECS"},
-	8443: {Name: "HTTPS Alt", Type: "webapps", AWSTarget: "EC2/ECS"},
-
-	// 7. BACKUPS / DR - Veeam, tapes, SAN snapshots
-	9392:  {Name: "Veeam", Type: "backups", AWSTarget: "AWS Backup"},
-	10006: {Name: "Veeam Data Mover", Type: "backups", AWSTarget: "S3 Glacier"},
-
-	// 8. LOGGING / MONITORING - Nagios, Zabbix, ELK, Splunk
-	9090: {Name: "Prometheus", Type: "monitoring", AWSTarget: "Managed Prometheus"},
-	9100: {Name: "Node Exporter", Type: "monitoring", AWSTarget: "CloudWatch"},
-	9200: {Name: "Elasticsearch", Type: "monitoring", AWSTarget: "OpenSearch"},
-	5601: {Name: "Kibana", Type: "monitoring", AWSTarget: "OpenSearch"},
-	514:  {Name: "Syslog", Type: "monitoring", AWSTarget: "CloudWatch"},
-
-	// 9. DEVOPS PIPELINES - Jenkins, GitLab, Bitbucket
-	8929: {Name: "GitLab", Type: "devops", AWSTarget: "CodePipeline"},
-	7990: {Name: "Bitbucket", Type: "devops", AWSTarget: "CodeCommit"},
-	// Note: Jenkins often on 8080, detected as web but can be refined
-
-	// 10. MESSAGING & QUEUES - RabbitMQ, Kafka, ActiveMQ
-	5672:  {Name: "RabbitMQ", Type: "messaging", AWSTarget: "SQS/MQ"},
-	9092:  {Name: "Kafka", Type: "messaging", AWSTarget: "MSK"},
-	61616: {Name: "ActiveMQ", Type: "messaging", AWSTarget: "MQ"},
-
-	// 11. EMAIL / SMTP - Exchange, SMTP relays
-	25:  {Name: "SMTP", Type: "email", AWSTarget: "SES"},
-	587: {Name: "SMTP Submission", Type: "email", AWSTarget: "SES"},
-	993: {Name: "IMAPS", Type: "email", AWSTarget: "WorkMail"},
-	995: {Name: "POP3S", Type: "email", AWSTarget: "WorkMail"},
-
-	// 12. BATCH JOBS / CRON - detected via SSH + process inspection
-	// No specific ports - these are identified by analyzing running processes
-}
+// Service identification used to be a static map of port -> ServiceInfo
+// here (THE 12 CATEGORIES THAT MATTER). It's now the
+// agent/internal/discovery/detectors registry: scanPort calls Probe with
+// every detector registered for the open port, and categorizeHost picks the
+// category of whichever detector reported the highest confidence.
 
 // ScanOptions configures the discovery scan
 type ScanOptions struct {
-	NetworkCIDR    string
-	Ports          []int
-	Timeout        time.Duration
-	Concurrency    int
-	DeepScan       bool // Do banner grabbing
-	IncludeOffline bool
+	NetworkCIDR       string
+	Ports             []int
+	Timeout           time.Duration
+	Concurrency       int
+	DeepScan          bool // Do banner grabbing
+	IncludeOffline    bool
+	EnableMDNS        bool                     // Also run passive mDNS/DNS-SD discovery
+	MDNSTimeout       time.Duration            // How long to listen for mDNS responses
+	Credentials       []inspect.HostCredential // SSH/WinRM creds for deep inspection of compute hosts
+	InspectTimeout    time.Duration            // Per-host budget for SSH inspection, default 30s
+	VulnDB            vuln.Database            // Optional CVE/EOL lookup; nil skips risk scoring
+	HighRiskThreshold int                      // RiskScore at/above which a host counts toward Summary.HighRiskHosts, default 70
+	Progress          progress.Progress        // Optional; reported against a count of hosts scanned
 }
 
 // DefaultScanOptions returns sensible defaults
 func DefaultScanOptions() ScanOptions {
 	return ScanOptions{
-		Ports:       commonPorts,
-		Timeout:     2 * time.Second,
-		Concurrency: 50,
-		DeepScan:    false,
+		Ports:             commonPorts,
+		Timeout:           2 * time.Second,
+		Concurrency:       50,
+		DeepScan:          false,
+		MDNSTimeout:       3 * time.Second,
+		HighRiskThreshold: defaultHighRiskThreshold,
 	}
 }
 
+// defaultHighRiskThreshold is the RiskScore at/above which a host counts
+// toward Summary.HighRiskHosts when ScanOptions.HighRiskThreshold is unset.
+const defaultHighRiskThreshold = 70
+
 // Scanner performs infrastructure discovery
 type Scanner struct {
 	options ScanOptions
@@ -197,6 +167,12 @@ func NewScanner(options ScanOptions) *Scanner {
 	if len(options.Ports) == 0 {
 		options.Ports = commonPorts
 	}
+	if options.MDNSTimeout == 0 {
+		options.MDNSTimeout = 3 * time.Second
+	}
+	if options.HighRiskThreshold == 0 {
+		options.HighRiskThreshold = defaultHighRiskThreshold
+	}
 	return &Scanner{options: options}
 }
 
@@ -219,6 +195,13 @@ func (s *Scanner) ScanNetwork(ctx context.Context, cidr string) (*DiscoveryResul
 		return nil, fmt.Errorf("invalid CIDR: %w", err)
 	}
 
+	reporter := s.options.Progress
+	if reporter == nil {
+		reporter = progress.Noop{}
+	}
+	reporter.Start(int64(len(ips)))
+	defer reporter.Finish()
+
 	// Scan hosts concurrently
 	var wg sync.WaitGroup
 	hostChan := make(chan DiscoveredHost, len(ips))
@@ -230,6 +213,7 @@ func (s *Scanner) ScanNetwork(ctx context.Context, cidr string) (*DiscoveryResul
 			defer wg.Done()
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
+			defer reporter.Add(1)
 
 			select {
 			case <-ctx.Done():
@@ -249,8 +233,26 @@ func (s *Scanner) ScanNetwork(ctx context.Context, cidr string) (*DiscoveryResul
 	}()
 
 	// Collect results
+	byIP := make(map[string]*DiscoveredHost)
 	for host := range hostChan {
-		result.Hosts = append(result.Hosts, host)
+		h := host
+		byIP[h.IP] = &h
+	}
+
+	if s.options.EnableMDNS {
+		mdnsHosts, err := s.DiscoverMDNS(ctx, s.options.MDNSTimeout)
+		if err != nil {
+			// mDNS is best-effort; a failure here shouldn't sink the whole scan.
+			log.FromContext(ctx).Warn("mDNS discovery failed", log.F("error", err.Error()))
+			mdnsHosts = nil
+		}
+		for _, mh := range mdnsHosts {
+			mergeMDNSHost(byIP, mh)
+		}
+	}
+
+	for _, host := range byIP {
+		result.Hosts = append(result.Hosts, *host)
 		result.Summary.TotalHosts++
 		if host.Status == "online" {
 			result.Summary.OnlineHosts++
@@ -261,12 +263,42 @@ func (s *Scanner) ScanNetwork(ctx context.Context, cidr string) (*DiscoveryResul
 		if host.OS != "" {
 			result.Summary.ByOS[host.OS]++
 		}
+		if host.RiskScore >= s.options.HighRiskThreshold {
+			result.Summary.HighRiskHosts++
+		}
 	}
 
 	result.CompletedAt = time.Now()
 	return result, nil
 }
 
+// mergeMDNSHost folds an mDNS-derived host into the TCP scan results, keyed
+// by IP, so hosts visible only over mDNS (e.g. firewalled printers) still
+// show up.
+func mergeMDNSHost(byIP map[string]*DiscoveredHost, mh DiscoveredHost) {
+	existing, ok := byIP[mh.IP]
+	if !ok {
+		byIP[mh.IP] = &mh
+		return
+	}
+
+	if existing.Hostname == "" {
+		existing.Hostname = mh.Hostname
+	}
+	if existing.Category == "" {
+		existing.Category = mh.Category
+	}
+	existing.Status = "online"
+	existing.OpenPorts = append(existing.OpenPorts, mh.OpenPorts...)
+	existing.Services = append(existing.Services, mh.Services...)
+	for k, v := range mh.Metadata {
+		if existing.Metadata == nil {
+			existing.Metadata = make(map[string]string)
+		}
+		existing.Metadata[k] = v
+	}
+}
+
 // scanHost scans a single host
 func (s *Scanner) scanHost(ctx context.Context, ip string) *DiscoveredHost {
 	host := &DiscoveredHost{
@@ -296,14 +328,14 @@ func (s *Scanner) scanHost(ctx context.Context, ip string) *DiscoveredHost {
 
 	// Port scan
 	var wg sync.WaitGroup
-	portChan := make(chan PortInfo, len(s.options.Ports))
+	portChan := make(chan portScanResult, len(s.options.Ports))
 
 	for _, port := range s.options.Ports {
 		wg.Add(1)
 		go func(p int) {
 			defer wg.Done()
-			if portInfo := s.scanPort(ip, p); portInfo != nil {
-				portChan <- *portInfo
+			if portInfo, detection := s.scanPort(ctx, ip, p); portInfo != nil {
+				portChan <- portScanResult{info: *portInfo, detection: detection}
 			}
 		}(port)
 	}
@@ -313,34 +345,122 @@ func (s *Scanner) scanHost(ctx context.Context, ip string) *DiscoveredHost {
 		close(portChan)
 	}()
 
-	for portInfo := range portChan {
-		host.OpenPorts = append(host.OpenPorts, portInfo)
-		
-		// Map to service
-		if sig, ok := serviceSignatures[portInfo.Port]; ok {
-			service := sig
-			service.Port = portInfo.Port
-			service.Confidence = 80
-			
-			// Try to get version from banner
-			if s.options.DeepScan && portInfo.Banner != "" {
-				service.Version = extractVersion(portInfo.Banner)
-				service.Confidence = 95
-			}
-			
-			host.Services = append(host.Services, service)
+	for pr := range portChan {
+		host.OpenPorts = append(host.OpenPorts, pr.info)
+
+		if pr.detection != nil {
+			host.Services = append(host.Services, ServiceInfo{
+				Name:       pr.detection.Name,
+				Type:       pr.detection.Category,
+				Version:    pr.detection.Version,
+				Port:       pr.info.Port,
+				Product:    pr.detection.Product,
+				AWSTarget:  pr.detection.AWSTarget,
+				Confidence: pr.detection.Confidence,
+			})
 		}
 	}
 
 	// Determine primary category and AWS target
 	host.Category, host.AWSTarget = s.categorizeHost(host)
-	
+
 	// Try to detect OS
 	host.OS = s.detectOS(host)
 
+	if len(s.options.Credentials) > 0 && host.Category == "compute" {
+		s.inspectHost(ctx, host)
+	}
+
+	if s.options.VulnDB != nil {
+		s.assessRisk(ctx, host)
+	}
+
 	return host
 }
 
+// assessRisk looks up each service's product/version against the
+// configured vuln.Database and rolls the worst-affected service's score up
+// to host.RiskScore - one severely vulnerable or EOL service is enough to
+// flag the whole host for migration planning.
+func (s *Scanner) assessRisk(ctx context.Context, host *DiscoveredHost) {
+	for i := range host.Services {
+		svc := &host.Services[i]
+		product := svc.Product
+		if product == "" {
+			product = svc.Name
+		}
+		if product == "" {
+			continue
+		}
+
+		assessment, err := s.options.VulnDB.Lookup(ctx, product, svc.Version)
+		if err != nil || assessment == nil {
+			continue
+		}
+
+		svc.CVEs = assessment.CVEs
+		svc.EndOfLife = assessment.EndOfLife
+
+		if score := vuln.Score(assessment); score > host.RiskScore {
+			host.RiskScore = score
+		}
+	}
+}
+
+// inspectHost runs the SSH-based deep inspection probes and folds the
+// findings back into host: refined OS/version, running daemons, scheduled
+// jobs (promoting the host to the batch category when any are found), and
+// ports the TCP scan missed because a firewall blocked them externally.
+func (s *Scanner) inspectHost(ctx context.Context, host *DiscoveredHost) {
+	timeout := s.options.InspectTimeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	ictx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	inspector := inspect.NewInspector(s.options.Credentials, s.options.Timeout)
+	result, err := inspector.Inspect(ictx, host.IP)
+	if err != nil {
+		return // no matching credential, auth failure, or connect failure - leave host as-is
+	}
+
+	if result.OS != "" {
+		host.OS = result.OS
+	}
+	if result.OSVersion != "" {
+		host.OSVersion = result.OSVersion
+	}
+
+	for _, svc := range result.Services {
+		host.Services = append(host.Services, ServiceInfo{Name: svc, Type: "compute", Confidence: 70})
+	}
+
+	for _, job := range result.ScheduledJobs {
+		host.ScheduledJobs = append(host.ScheduledJobs, JobInfo{
+			Name:     job.Name,
+			Schedule: job.Schedule,
+			Command:  job.Command,
+			Source:   job.Source,
+		})
+	}
+	if len(result.ScheduledJobs) > 0 {
+		host.Category = "batch"
+	}
+
+	existingPorts := make(map[int]bool, len(host.OpenPorts))
+	for _, p := range host.OpenPorts {
+		existingPorts[p.Port] = true
+	}
+	for _, port := range result.ExtraPorts {
+		if existingPorts[port] {
+			continue
+		}
+		host.OpenPorts = append(host.OpenPorts, PortInfo{Port: port, Protocol: "tcp", State: "open"})
+		existingPorts[port] = true
+	}
+}
+
 // isHostAlive checks if a host responds to ping or TCP connect
 func (s *Scanner) isHostAlive(ip string) bool {
 	// Try TCP connect to common ports first (faster than ping)
@@ -360,16 +480,24 @@ func (s *Scanner) isHostAlive(ip string) bool {
 	} else {
 		cmd = exec.Command("ping", "-c", "1", "-W", "1", ip)
 	}
-	
+
 	return cmd.Run() == nil
 }
 
-// scanPort checks if a port is open
-func (s *Scanner) scanPort(ip string, port int) *PortInfo {
+// portScanResult pairs a discovered open port with whatever detector, if
+// any, recognized the service listening on it.
+type portScanResult struct {
+	info      PortInfo
+	detection *detectors.Result
+}
+
+// scanPort checks if a port is open and, if so, runs every registered
+// detector for that port to identify the service.
+func (s *Scanner) scanPort(ctx context.Context, ip string, port int) (*PortInfo, *detectors.Result) {
 	address := fmt.Sprintf("%s:%d", ip, port)
 	conn, err := net.DialTimeout("tcp", address, s.options.Timeout)
 	if err != nil {
-		return nil
+		return nil, nil
 	}
 	defer conn.Close()
 
@@ -379,13 +507,16 @@ func (s *Scanner) scanPort(ip string, port int) *PortInfo {
 		State:    "open",
 	}
 
-	// Get service name
-	if sig, ok := serviceSignatures[port]; ok {
-		portInfo.Service = sig.Name
+	detection := detectors.Probe(ctx, port, func() (net.Conn, error) {
+		return net.DialTimeout("tcp", address, s.options.Timeout)
+	})
+	if detection != nil {
+		portInfo.Service = detection.Name
 	}
 
-	// Banner grab if deep scan
-	if s.options.DeepScan {
+	// Fall back to a raw banner grab on deep scans when no detector claimed
+	// the port - detectors that need a banner already consumed it.
+	if s.options.DeepScan && detection == nil {
 		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
 		buf := make([]byte, 1024)
 		if n, err := conn.Read(buf); err == nil && n > 0 {
@@ -393,41 +524,23 @@ func (s *Scanner) scanPort(ip string, port int) *PortInfo {
 		}
 	}
 
-	return portInfo
+	return portInfo, detection
 }
 
-// categorizeHost determines the primary category based on services
-// Uses THE 12 CATEGORIES THAT MATTER
+// categorizeHost determines the primary category and AWS migration target
+// from whichever of the host's detected services was identified with the
+// highest confidence.
 func (s *Scanner) categorizeHost(host *DiscoveredHost) (string, string) {
-	// Priority order - databases and identity are high value, compute is default
-	priorities := []string{
-		"databases",   // 2. Databases
-		"identity",    // 4. Identity
-		"email",       // 11. Email
-		"messaging",   // 10. Messaging
-		"storage",     // 3. File Storage
-		"webapps",     // 6. Web Apps
-		"monitoring",  // 8. Logging/Monitoring
-		"devops",      // 9. DevOps
-		"backups",     // 7. Backups/DR
-		"networking",  // 5. Networks/VPN
-		"compute",     // 1. Compute (default)
-	}
-	
-	categoryCount := make(map[string]int)
-	var primaryAWS string
-	
-	for _, svc := range host.Services {
-		categoryCount[svc.Type]++
-		if primaryAWS == "" {
-			primaryAWS = svc.AWSTarget
+	var best *ServiceInfo
+	for i := range host.Services {
+		svc := &host.Services[i]
+		if best == nil || svc.Confidence > best.Confidence {
+			best = svc
 		}
 	}
 
-	for _, cat := range priorities {
-		if categoryCount[cat] > 0 {
-			return cat, primaryAWS
-		}
+	if best != nil && best.Type != "" {
+		return best.Type, best.AWSTarget
 	}
 
 	// Default based on open ports
@@ -527,26 +640,6 @@ func incrementIP(ip net.IP) {
 	}
 }
 
-// extractVersion tries to extract version from banner
-func extractVersion(banner string) string {
-	// Common version patterns
-	patterns := []string{
-		`(\d+\.\d+\.\d+)`,
-		`(\d+\.\d+)`,
-		`version[:\s]+(\S+)`,
-		`ver[:\s]+(\S+)`,
-	}
-
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		if matches := re.FindStringSubmatch(banner); len(matches) > 1 {
-			return matches[1]
-		}
-	}
-
-	return ""
-}
-
 // GetLocalNetworks returns the local network CIDRs
 func GetLocalNetworks() ([]string, error) {
 	var networks []string
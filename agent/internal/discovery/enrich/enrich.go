@@ -0,0 +1,174 @@
+// Package enrich applies user-defined labeling rules (context.yaml) to
+// discovered hosts, independent of the discovery package's host model so it
+// can be reused without import cycles.
+package enrich
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath returns the conventional location of context.yaml, kept
+// alongside the agent's other local state (~/.cloudmigrate/context.yaml).
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".cloudmigrate", "context.yaml")
+	}
+	return filepath.Join(home, ".cloudmigrate", "context.yaml")
+}
+
+// Host is the minimal view of a discovered host that rules are matched
+// against. Callers in internal/discovery build one of these from a
+// DiscoveredHost before calling Apply.
+type Host struct {
+	IP       string
+	Hostname string
+	OS       string
+	Services []string // service names, e.g. "SSH", "MySQL"
+	Ports    []int
+}
+
+// Match describes the predicates a rule combines with AND. Empty fields are
+// ignored.
+type Match struct {
+	CIDR     string `yaml:"cidr,omitempty"`
+	Service  string `yaml:"service,omitempty"`
+	Port     int    `yaml:"port,omitempty"`
+	Hostname string `yaml:"hostname,omitempty"` // regex
+	OS       string `yaml:"os,omitempty"`
+
+	cidrNet    *net.IPNet
+	hostnameRe *regexp.Regexp
+}
+
+// Rule is a single labeling rule loaded from context.yaml.
+type Rule struct {
+	Name   string            `yaml:"name,omitempty"`
+	Match  Match             `yaml:"match"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+// config is the on-disk shape of context.yaml.
+type config struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// LoadRules reads and compiles rules from a context.yaml file at path. A
+// missing file is not an error: callers should treat it as "no enrichment
+// configured".
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read context file: %w", err)
+	}
+
+	var cfg config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse context file: %w", err)
+	}
+
+	for i := range cfg.Rules {
+		if err := cfg.Rules[i].Match.compile(); err != nil {
+			name := cfg.Rules[i].Name
+			if name == "" {
+				name = fmt.Sprintf("rule %d", i+1)
+			}
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return cfg.Rules, nil
+}
+
+func (m *Match) compile() error {
+	if m.CIDR != "" {
+		_, ipnet, err := net.ParseCIDR(m.CIDR)
+		if err != nil {
+			return fmt.Errorf("invalid cidr %q: %w", m.CIDR, err)
+		}
+		m.cidrNet = ipnet
+	}
+	if m.Hostname != "" {
+		re, err := regexp.Compile(m.Hostname)
+		if err != nil {
+			return fmt.Errorf("invalid hostname pattern %q: %w", m.Hostname, err)
+		}
+		m.hostnameRe = re
+	}
+	return nil
+}
+
+// Matches reports whether host satisfies every predicate set on m.
+func (m *Match) Matches(host Host) bool {
+	if m.cidrNet != nil {
+		ip := net.ParseIP(host.IP)
+		if ip == nil || !m.cidrNet.Contains(ip) {
+			return false
+		}
+	}
+	if m.Service != "" && !hasService(host.Services, m.Service) {
+		return false
+	}
+	if m.Port != 0 && !hasPort(host.Ports, m.Port) {
+		return false
+	}
+	if m.hostnameRe != nil && !m.hostnameRe.MatchString(host.Hostname) {
+		return false
+	}
+	if m.OS != "" && !strings.EqualFold(host.OS, m.OS) {
+		return false
+	}
+	return true
+}
+
+func hasService(services []string, name string) bool {
+	for _, s := range services {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply evaluates every rule against host and returns the merged label set.
+// Rules are evaluated in order; later matching rules override earlier labels
+// with the same key.
+func Apply(rules []Rule, host Host) map[string]string {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]string)
+	for _, rule := range rules {
+		if !rule.Match.Matches(host) {
+			continue
+		}
+		for k, v := range rule.Labels {
+			labels[k] = v
+		}
+	}
+
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
@@ -0,0 +1,47 @@
+package inspect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// credentialConfig is the on-disk shape of ssh-creds.yaml: a list of
+// HostCredential entries an operator configures to enable deep inspection,
+// without which ScanOptions.Credentials stays empty and inspection is
+// skipped entirely.
+type credentialConfig struct {
+	Credentials []HostCredential `yaml:"credentials"`
+}
+
+// DefaultConfigPath returns the conventional location of ssh-creds.yaml,
+// kept alongside the agent's other local state.
+func DefaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".cloudmigrate", "ssh-creds.yaml")
+	}
+	return filepath.Join(home, ".cloudmigrate", "ssh-creds.yaml")
+}
+
+// LoadCredentials reads ssh-creds.yaml at path into a []HostCredential. A
+// missing file is not an error: callers should treat it as "no credentials
+// configured", which leaves deep inspection disabled.
+func LoadCredentials(path string) ([]HostCredential, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read credentials file: %w", err)
+	}
+
+	var cfg credentialConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse credentials file: %w", err)
+	}
+
+	return cfg.Credentials, nil
+}
@@ -0,0 +1,363 @@
+// Package inspect performs read-only SSH-based deep inspection of online
+// compute hosts to fill in OS/package data and scheduled jobs that a plain
+// TCP port scan cannot see. It mirrors the remote-scan pattern used by
+// vulnerability scanners: a fixed, table-driven catalog of probes is run
+// over a single connection and parsed into structured results.
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// AuthMethod identifies how to authenticate a HostCredential.
+type AuthMethod string
+
+const (
+	AuthSSHKey      AuthMethod = "ssh-key"
+	AuthSSHPassword AuthMethod = "ssh-password"
+	AuthWinRM       AuthMethod = "winrm"
+)
+
+// HostCredential describes one set of credentials to try against matching
+// hosts. CIDR is optional; an empty CIDR matches any host, letting a single
+// fallback credential be configured alongside more specific overrides.
+type HostCredential struct {
+	CIDR     string     `yaml:"cidr,omitempty"`
+	Method   AuthMethod `yaml:"method"`
+	Username string     `yaml:"username"`
+	Password string     `yaml:"password,omitempty"`
+	KeyPath  string     `yaml:"keyPath,omitempty"`
+	Port     int        `yaml:"port,omitempty"` // defaults to 22 for SSH methods
+}
+
+func (c HostCredential) matches(ip string) bool {
+	if c.CIDR == "" {
+		return true
+	}
+	_, ipnet, err := net.ParseCIDR(c.CIDR)
+	if err != nil {
+		return false
+	}
+	parsed := net.ParseIP(ip)
+	return parsed != nil && ipnet.Contains(parsed)
+}
+
+// JobInfo describes a scheduled job found on the host.
+type JobInfo struct {
+	Name     string
+	Schedule string
+	Command  string
+	Source   string
+}
+
+// Result is everything the Inspector learned about a single host.
+type Result struct {
+	OS            string
+	OSVersion     string
+	Services      []string // long-running daemons observed in the process list
+	Packages      []string
+	ScheduledJobs []JobInfo
+	ExtraPorts    []int   // open ports ss/netstat saw that the TCP scan missed
+	ProbeErrors   []error // one per failed probe; the remaining probes still run
+}
+
+// osFamily is used to pick which probe command variant to run.
+type osFamily string
+
+const (
+	osLinux   osFamily = "linux"
+	osUnknown osFamily = "unknown"
+)
+
+// probe is one read-only diagnostic command in the catalog. Commands are
+// plain POSIX shell so they work across the Linux distributions we target;
+// a probe that doesn't apply to a host (e.g. no systemd) is expected to fail
+// harmlessly and is skipped.
+type probe struct {
+	name    string
+	family  osFamily
+	command string
+	timeout time.Duration
+	parse   func(output string, r *Result)
+}
+
+var probeCatalog = []probe{
+	{
+		name:    "os-release",
+		family:  osLinux,
+		command: "cat /etc/os-release 2>/dev/null || uname -a",
+		timeout: 5 * time.Second,
+		parse:   parseOSRelease,
+	},
+	{
+		name:    "scheduled-jobs",
+		family:  osLinux,
+		command: "crontab -l 2>/dev/null; echo ---; ls /etc/cron.d /etc/cron.daily /etc/cron.hourly 2>/dev/null; echo ---; systemctl list-timers --all --no-legend 2>/dev/null",
+		timeout: 10 * time.Second,
+		parse:   parseScheduledJobs,
+	},
+	{
+		name:    "processes",
+		family:  osLinux,
+		command: "ps -eo comm,args 2>/dev/null",
+		timeout: 5 * time.Second,
+		parse:   parseProcesses,
+	},
+	{
+		name:    "packages",
+		family:  osLinux,
+		command: "dpkg -l 2>/dev/null || rpm -qa 2>/dev/null",
+		timeout: 15 * time.Second,
+		parse:   parsePackages,
+	},
+	{
+		name:    "listening-ports",
+		family:  osLinux,
+		command: "ss -tlnp 2>/dev/null || netstat -tlnp 2>/dev/null",
+		timeout: 5 * time.Second,
+		parse:   parseListeningPorts,
+	},
+}
+
+// knownDaemons flags the long-running processes we promote into
+// Result.Services. This mirrors the short-list approach the port scanner
+// already uses for its service signatures.
+var knownDaemons = []string{"jenkins", "java", "tomcat", "postgres", "mysqld", "nginx", "httpd", "dockerd"}
+
+// Inspector runs the probe catalog over SSH against online compute hosts.
+type Inspector struct {
+	credentials     []HostCredential
+	connectTimeout  time.Duration
+	perProbeTimeout time.Duration
+}
+
+// NewInspector builds an Inspector. connectTimeout, if zero, defaults to 5s;
+// each probe runs under its own timeout from the catalog.
+func NewInspector(credentials []HostCredential, connectTimeout time.Duration) *Inspector {
+	if connectTimeout == 0 {
+		connectTimeout = 5 * time.Second
+	}
+	return &Inspector{credentials: credentials, connectTimeout: connectTimeout}
+}
+
+// Inspect connects to ip using the first matching credential and runs the
+// probe catalog, recording each failed probe's error in Result.ProbeErrors
+// instead of aborting the whole inspection when one probe fails.
+func (insp *Inspector) Inspect(ctx context.Context, ip string) (*Result, error) {
+	cred, ok := insp.credentialFor(ip)
+	if !ok {
+		return nil, fmt.Errorf("no credential configured for %s", ip)
+	}
+
+	switch cred.Method {
+	case AuthWinRM:
+		return nil, fmt.Errorf("winrm inspection not yet implemented")
+	case AuthSSHKey, AuthSSHPassword:
+		return insp.inspectSSH(ctx, ip, cred)
+	default:
+		return nil, fmt.Errorf("unknown credential method %q", cred.Method)
+	}
+}
+
+func (insp *Inspector) credentialFor(ip string) (HostCredential, bool) {
+	for _, c := range insp.credentials {
+		if c.matches(ip) {
+			return c, true
+		}
+	}
+	return HostCredential{}, false
+}
+
+func (insp *Inspector) inspectSSH(ctx context.Context, ip string, cred HostCredential) (*Result, error) {
+	auth, err := sshAuthMethod(cred)
+	if err != nil {
+		return nil, err
+	}
+
+	port := cred.Port
+	if port == 0 {
+		port = 22
+	}
+
+	config := &ssh.ClientConfig{
+		User:            cred.Username,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // read-only inventory probe, not a trust boundary
+		Timeout:         insp.connectTimeout,
+	}
+
+	client, err := ssh.Dial("tcp", fmt.Sprintf("%s:%d", ip, port), config)
+	if err != nil {
+		return nil, fmt.Errorf("ssh dial %s: %w", ip, err)
+	}
+	defer client.Close()
+
+	result := &Result{OS: string(osLinux)}
+	for _, p := range probeCatalog {
+		out, err := runProbe(ctx, client, p)
+		if err != nil {
+			// A single failing probe (missing tool, permission denied) must
+			// not kill the rest of the inspection.
+			result.ProbeErrors = append(result.ProbeErrors, err)
+			continue
+		}
+		p.parse(out, result)
+	}
+
+	return result, nil
+}
+
+func sshAuthMethod(cred HostCredential) (ssh.AuthMethod, error) {
+	switch cred.Method {
+	case AuthSSHPassword:
+		return ssh.Password(cred.Password), nil
+	case AuthSSHKey:
+		keyData, err := os.ReadFile(cred.KeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("read private key %s: %w", cred.KeyPath, err)
+		}
+		signer, err := ssh.ParsePrivateKey(keyData)
+		if err != nil {
+			return nil, fmt.Errorf("parse private key: %w", err)
+		}
+		return ssh.PublicKeys(signer), nil
+	default:
+		return nil, fmt.Errorf("unsupported ssh auth method %q", cred.Method)
+	}
+}
+
+func runProbe(ctx context.Context, client *ssh.Client, p probe) (string, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("open session: %w", err)
+	}
+	defer session.Close()
+
+	timeout := p.timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	session.Stdout = &out
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(p.command) }()
+
+	select {
+	case <-ctx.Done():
+		session.Signal(ssh.SIGKILL)
+		return "", fmt.Errorf("probe %s timed out", p.name)
+	case err := <-done:
+		if err != nil {
+			return "", fmt.Errorf("probe %s: %w", p.name, err)
+		}
+		return out.String(), nil
+	}
+}
+
+var osReleasePattern = regexp.MustCompile(`(?m)^PRETTY_NAME="?([^"\n]+)"?`)
+var versionIDPattern = regexp.MustCompile(`(?m)^VERSION_ID="?([^"\n]+)"?`)
+
+func parseOSRelease(output string, r *Result) {
+	if m := osReleasePattern.FindStringSubmatch(output); len(m) > 1 {
+		r.OS = m[1]
+	} else if strings.TrimSpace(output) != "" {
+		r.OS = strings.TrimSpace(strings.SplitN(output, "\n", 2)[0])
+	}
+	if m := versionIDPattern.FindStringSubmatch(output); len(m) > 1 {
+		r.OSVersion = m[1]
+	}
+}
+
+func parseScheduledJobs(output string, r *Result) {
+	sections := strings.Split(output, "---")
+	if len(sections) > 0 {
+		for _, line := range strings.Split(strings.TrimSpace(sections[0]), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			r.ScheduledJobs = append(r.ScheduledJobs, JobInfo{Command: line, Source: "crontab"})
+		}
+	}
+	if len(sections) > 1 {
+		for _, name := range strings.Fields(sections[1]) {
+			r.ScheduledJobs = append(r.ScheduledJobs, JobInfo{Name: name, Source: "cron.d"})
+		}
+	}
+	if len(sections) > 2 {
+		for _, line := range strings.Split(strings.TrimSpace(sections[2]), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			r.ScheduledJobs = append(r.ScheduledJobs, JobInfo{
+				Name:     fields[0],
+				Schedule: strings.Join(fields[1:], " "),
+				Source:   "systemd-timer",
+			})
+		}
+	}
+}
+
+func parseProcesses(output string, r *Result) {
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		comm := strings.ToLower(fields[0])
+		for _, known := range knownDaemons {
+			if strings.Contains(comm, known) && !seen[known] {
+				seen[known] = true
+				r.Services = append(r.Services, known)
+			}
+		}
+	}
+}
+
+func parsePackages(output string, r *Result) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		switch {
+		case strings.HasPrefix(line, "ii"): // dpkg -l
+			if len(fields) > 1 {
+				r.Packages = append(r.Packages, fields[1])
+			}
+		default: // rpm -qa
+			r.Packages = append(r.Packages, fields[0])
+		}
+	}
+}
+
+var listenPortPattern = regexp.MustCompile(`:(\d+)\s`)
+
+func parseListeningPorts(output string, r *Result) {
+	seen := make(map[int]bool)
+	for _, m := range listenPortPattern.FindAllStringSubmatch(output, -1) {
+		var port int
+		fmt.Sscanf(m[1], "%d", &port)
+		if port != 0 && !seen[port] {
+			seen[port] = true
+			r.ExtraPorts = append(r.ExtraPorts, port)
+		}
+	}
+}
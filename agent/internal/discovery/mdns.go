@@ -0,0 +1,488 @@
+package discovery
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	mdnsPort                 = 5353
+	mdnsServicesQuery        = "_services._dns-sd._udp.local."
+	dnsTypePTR        uint16 = 12
+	dnsTypeTXT        uint16 = 16
+	dnsTypeSRV        uint16 = 33
+	dnsTypeA          uint16 = 1
+	dnsTypeAAAA       uint16 = 28
+	dnsClassIN        uint16 = 1
+)
+
+var mdnsIPv4Group = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: mdnsPort}
+var mdnsIPv6Group = &net.UDPAddr{IP: net.ParseIP("ff02::fb"), Port: mdnsPort}
+
+// mdnsServiceTypes are the DNS-SD service types we actively browse for: each
+// gets one PTR query per interface, sent alongside the
+// _services._dns-sd._udp.local. meta-query. Service types revealed only by
+// the meta-query are not queried in turn.
+var mdnsServiceTypes = []string{
+	"_workstation._tcp.local.",
+	"_ssh._tcp.local.",
+	"_smb._tcp.local.",
+	"_ipp._tcp.local.",
+	"_airplay._tcp.local.",
+	"_raop._tcp.local.",
+	"_printer._tcp.local.",
+	"_googlecast._tcp.local.",
+}
+
+// mdnsCategoryByServiceType maps a DNS-SD service type to the discovery
+// category a host advertising it should be placed in.
+var mdnsCategoryByServiceType = map[string]string{
+	"_ssh._tcp.local.":         "compute",
+	"_smb._tcp.local.":         "storage",
+	"_workstation._tcp.local.": "compute",
+	"_ipp._tcp.local.":         "devices",
+	"_printer._tcp.local.":     "devices",
+	"_airplay._tcp.local.":     "devices",
+	"_raop._tcp.local.":        "devices",
+	"_googlecast._tcp.local.":  "devices",
+}
+
+// mdnsRecord is a single discovered (name, service, host, port, txt) tuple
+// merged into a DiscoveredHost by IP once resolution completes.
+type mdnsRecord struct {
+	serviceType string
+	instance    string
+	target      string // SRV target hostname
+	port        int
+	ips         []string
+	txt         map[string]string
+}
+
+// DiscoverMDNS passively listens for mDNS/DNS-SD announcements on every
+// non-loopback, multicast-capable interface and returns hosts assembled from
+// PTR/SRV/TXT/A/AAAA records. It never returns an error for a single
+// interface failing to join the multicast group - that interface is simply
+// skipped, since many environments (containers, some VPN adapters) cannot
+// bind multicast sockets.
+func (s *Scanner) DiscoverMDNS(ctx context.Context, timeout time.Duration) ([]DiscoveredHost, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("list interfaces: %w", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		records = make(map[string]*mdnsRecord) // keyed by serviceType+"|"+instance
+		wg      sync.WaitGroup
+	)
+
+	addRecord := func(rec *mdnsRecord) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := rec.serviceType + "|" + rec.instance
+		existing, ok := records[key]
+		if !ok {
+			records[key] = rec
+			return
+		}
+		if rec.target != "" {
+			existing.target = rec.target
+		}
+		if rec.port != 0 {
+			existing.port = rec.port
+		}
+		existing.ips = append(existing.ips, rec.ips...)
+		for k, v := range rec.txt {
+			if existing.txt == nil {
+				existing.txt = make(map[string]string)
+			}
+			existing.txt[k] = v
+		}
+	}
+
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagMulticast == 0 {
+			continue
+		}
+
+		iface := iface
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.browseInterface(ctx, iface, timeout, addRecord)
+		}()
+	}
+
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	hosts := make(map[string]*DiscoveredHost)
+	for _, rec := range records {
+		if len(rec.ips) == 0 {
+			continue
+		}
+		for _, ip := range rec.ips {
+			host, ok := hosts[ip]
+			if !ok {
+				host = &DiscoveredHost{
+					IP:        ip,
+					Status:    "online",
+					OpenPorts: []PortInfo{},
+					Services:  []ServiceInfo{},
+					Metadata:  make(map[string]string),
+					LastSeen:  time.Now(),
+				}
+				hosts[ip] = host
+			}
+			if host.Hostname == "" {
+				host.Hostname = strings.TrimSuffix(rec.target, ".")
+			}
+			if rec.port != 0 {
+				host.OpenPorts = append(host.OpenPorts, PortInfo{
+					Port:     rec.port,
+					Protocol: "tcp",
+					State:    "open",
+					Service:  serviceName(rec.serviceType),
+				})
+				host.Services = append(host.Services, ServiceInfo{
+					Name:       serviceName(rec.serviceType),
+					Type:       mdnsCategoryByServiceType[rec.serviceType],
+					Port:       rec.port,
+					Confidence: 90,
+				})
+			}
+			if cat, ok := mdnsCategoryByServiceType[rec.serviceType]; ok && host.Category == "" {
+				host.Category = cat
+			}
+			for k, v := range rec.txt {
+				host.Metadata[k] = v
+			}
+		}
+	}
+
+	result := make([]DiscoveredHost, 0, len(hosts))
+	for _, h := range hosts {
+		result = append(result, *h)
+	}
+	return result, nil
+}
+
+// browseInterface joins the mDNS multicast groups on iface, fires the
+// discovery + well-known service queries, and feeds parsed records to emit
+// until timeout elapses.
+func (s *Scanner) browseInterface(ctx context.Context, iface net.Interface, timeout time.Duration, emit func(*mdnsRecord)) {
+	conn4, err4 := net.ListenMulticastUDP("udp4", &iface, mdnsIPv4Group)
+	conn6, err6 := net.ListenMulticastUDP("udp6", &iface, mdnsIPv6Group)
+	if err4 != nil && err6 != nil {
+		// Multicast bind failed on both stacks for this interface - skip it.
+		return
+	}
+	if conn4 != nil {
+		defer conn4.Close()
+	}
+	if conn6 != nil {
+		defer conn6.Close()
+	}
+
+	deadline := time.Now().Add(timeout)
+	if conn4 != nil {
+		conn4.SetReadDeadline(deadline)
+	}
+	if conn6 != nil {
+		conn6.SetReadDeadline(deadline)
+	}
+
+	queries := append([]string{mdnsServicesQuery}, mdnsServiceTypes...)
+	for _, q := range queries {
+		pkt := encodeQuestion(q, dnsTypePTR)
+		if conn4 != nil {
+			conn4.WriteToUDP(pkt, mdnsIPv4Group)
+		}
+		if conn6 != nil {
+			conn6.WriteToUDP(pkt, mdnsIPv6Group)
+		}
+	}
+
+	var wg sync.WaitGroup
+	if conn4 != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readResponses(ctx, conn4, emit)
+		}()
+	}
+	if conn6 != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readResponses(ctx, conn6, emit)
+		}()
+	}
+	wg.Wait()
+}
+
+func readResponses(ctx context.Context, conn *net.UDPConn, emit func(*mdnsRecord)) {
+	buf := make([]byte, 65535)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return // deadline exceeded, or socket closed
+		}
+
+		for _, rec := range parseMDNSMessage(buf[:n]) {
+			emit(rec)
+		}
+	}
+}
+
+// parseMDNSMessage decodes the answer + additional sections of a DNS
+// message into mdnsRecords, tolerating malformed packets by returning
+// whatever was parsed so far.
+func parseMDNSMessage(msg []byte) []*mdnsRecord {
+	if len(msg) < 12 {
+		return nil
+	}
+
+	qdCount := binary.BigEndian.Uint16(msg[4:6])
+	anCount := binary.BigEndian.Uint16(msg[6:8])
+	nsCount := binary.BigEndian.Uint16(msg[8:10])
+	arCount := binary.BigEndian.Uint16(msg[10:12])
+
+	off := 12
+	for i := 0; i < int(qdCount); i++ {
+		_, newOff, ok := decodeName(msg, off)
+		if !ok || newOff+4 > len(msg) {
+			return nil
+		}
+		off = newOff + 4 // QTYPE + QCLASS
+	}
+
+	byInstance := make(map[string]*mdnsRecord)
+	ptrServices := make(map[string]string) // name -> serviceType pointed to
+
+	total := int(anCount) + int(nsCount) + int(arCount)
+	for i := 0; i < total; i++ {
+		name, newOff, ok := decodeName(msg, off)
+		if !ok || newOff+10 > len(msg) {
+			return collectRecords(byInstance)
+		}
+		off = newOff
+
+		rrType := binary.BigEndian.Uint16(msg[off : off+2])
+		off += 2
+		off += 2 // class
+		off += 4 // ttl
+		rdlen := int(binary.BigEndian.Uint16(msg[off : off+2]))
+		off += 2
+		if off+rdlen > len(msg) {
+			return collectRecords(byInstance)
+		}
+		rdata := msg[off : off+rdlen]
+		rdStart := off
+		off += rdlen
+
+		switch rrType {
+		case dnsTypePTR:
+			target, _, ok := decodeName(msg, rdStart)
+			if ok {
+				ptrServices[target] = name
+			}
+		case dnsTypeSRV:
+			if len(rdata) < 6 {
+				continue
+			}
+			port := int(binary.BigEndian.Uint16(rdata[4:6]))
+			target, _, ok := decodeName(msg, rdStart+6)
+			if !ok {
+				continue
+			}
+			rec := recordFor(byInstance, name)
+			rec.port = port
+			rec.target = target
+		case dnsTypeTXT:
+			rec := recordFor(byInstance, name)
+			if rec.txt == nil {
+				rec.txt = make(map[string]string)
+			}
+			for k, v := range decodeTXT(rdata) {
+				rec.txt[k] = v
+			}
+		case dnsTypeA:
+			if len(rdata) != 4 {
+				continue
+			}
+			ip := net.IP(rdata).String()
+			attachAddress(byInstance, name, ip)
+		case dnsTypeAAAA:
+			if len(rdata) != 16 {
+				continue
+			}
+			ip := net.IP(rdata).String()
+			attachAddress(byInstance, name, ip)
+		}
+	}
+
+	// Associate PTR pointers (instance -> service type) with their records.
+	for instance, serviceType := range ptrServices {
+		rec := recordFor(byInstance, instance)
+		rec.serviceType = serviceType
+		rec.instance = instance
+	}
+
+	return collectRecords(byInstance)
+}
+
+func recordFor(m map[string]*mdnsRecord, name string) *mdnsRecord {
+	rec, ok := m[name]
+	if !ok {
+		rec = &mdnsRecord{instance: name}
+		m[name] = rec
+	}
+	return rec
+}
+
+// attachAddress records an A/AAAA answer against every pending record whose
+// SRV target matches name (targets are resolved after SRV is seen, so this
+// also covers the case where the address arrives before the SRV record by
+// stashing it under the target's own name).
+func attachAddress(m map[string]*mdnsRecord, name, ip string) {
+	rec := recordFor(m, name)
+	rec.ips = append(rec.ips, ip)
+	for _, other := range m {
+		if other != rec && other.target == name {
+			other.ips = append(other.ips, ip)
+		}
+	}
+}
+
+func collectRecords(m map[string]*mdnsRecord) []*mdnsRecord {
+	out := make([]*mdnsRecord, 0, len(m))
+	for _, rec := range m {
+		if rec.serviceType == "" {
+			continue // plain address record with no associated service
+		}
+		// Address may have been attached under the SRV target's name rather
+		// than the instance name; pull it over if so.
+		if len(rec.ips) == 0 && rec.target != "" {
+			if targetRec, ok := m[rec.target]; ok {
+				rec.ips = append(rec.ips, targetRec.ips...)
+			}
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+// decodeTXT splits a TXT record's length-prefixed strings into key=value
+// pairs, keeping bare flags (no "=") as key with an empty value.
+func decodeTXT(rdata []byte) map[string]string {
+	out := make(map[string]string)
+	i := 0
+	for i < len(rdata) {
+		l := int(rdata[i])
+		i++
+		if i+l > len(rdata) {
+			break
+		}
+		entry := string(rdata[i : i+l])
+		i += l
+
+		if idx := strings.IndexByte(entry, '='); idx >= 0 {
+			out[entry[:idx]] = entry[idx+1:]
+		} else if entry != "" {
+			out[entry] = ""
+		}
+	}
+	return out
+}
+
+// decodeName decodes a (possibly compressed) DNS name starting at off,
+// returning the name and the offset immediately following it (not following
+// any compression pointer it may have jumped through).
+func decodeName(msg []byte, off int) (string, int, bool) {
+	var labels []string
+	jumped := false
+	endOff := off
+
+	for i := 0; i < 128; i++ { // bound pointer chains against malformed loops
+		if off >= len(msg) {
+			return "", 0, false
+		}
+		l := int(msg[off])
+		if l == 0 {
+			off++
+			if !jumped {
+				endOff = off
+			}
+			return strings.Join(labels, ".") + ".", endOff, true
+		}
+		if l&0xC0 == 0xC0 {
+			if off+1 >= len(msg) {
+				return "", 0, false
+			}
+			ptr := (int(l&0x3F) << 8) | int(msg[off+1])
+			if !jumped {
+				endOff = off + 2
+			}
+			jumped = true
+			off = ptr
+			continue
+		}
+		off++
+		if off+l > len(msg) {
+			return "", 0, false
+		}
+		labels = append(labels, string(msg[off:off+l]))
+		off += l
+	}
+
+	return "", 0, false
+}
+
+// encodeQuestion builds a minimal one-question mDNS query packet.
+func encodeQuestion(name string, qtype uint16) []byte {
+	buf := make([]byte, 12)
+	binary.BigEndian.PutUint16(buf[4:6], 1) // QDCOUNT
+
+	buf = append(buf, encodeName(name)...)
+	tail := make([]byte, 4)
+	binary.BigEndian.PutUint16(tail[0:2], qtype)
+	binary.BigEndian.PutUint16(tail[2:4], dnsClassIN)
+	return append(buf, tail...)
+}
+
+func encodeName(name string) []byte {
+	var buf []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if label == "" {
+			continue
+		}
+		buf = append(buf, byte(len(label)))
+		buf = append(buf, []byte(label)...)
+	}
+	buf = append(buf, 0)
+	return buf
+}
+
+// serviceName derives a short human-readable name from a DNS-SD service
+// type, e.g. "_ssh._tcp.local." -> "SSH".
+func serviceName(serviceType string) string {
+	name := strings.TrimPrefix(serviceType, "_")
+	if idx := strings.Index(name, "."); idx >= 0 {
+		name = name[:idx]
+	}
+	return strings.ToUpper(name)
+}
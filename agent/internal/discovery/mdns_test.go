@@ -0,0 +1,252 @@
+package discovery
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestEncodeDecodeNameRoundTrip(t *testing.T) {
+	tests := []string{
+		"_ssh._tcp.local.",
+		"myhost.local.",
+		"a.b.c.local.",
+	}
+
+	for _, name := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoded := encodeName(name)
+			msg := append(append([]byte{}, make([]byte, 12)...), encoded...)
+			got, off, ok := decodeName(msg, 12)
+			if !ok {
+				t.Fatalf("decodeName failed to decode %q", name)
+			}
+			if got != name {
+				t.Errorf("decodeName = %q, want %q", got, name)
+			}
+			if off != len(msg) {
+				t.Errorf("decodeName returned offset %d, want %d (end of message)", off, len(msg))
+			}
+		})
+	}
+}
+
+func TestDecodeNameFollowsCompressionPointer(t *testing.T) {
+	// Message layout: header (12 bytes) + "myhost.local." at offset 12,
+	// then a second name at offset 12+len that's just a pointer back to 12.
+	msg := make([]byte, 12)
+	target := encodeName("myhost.local.")
+	targetOff := len(msg)
+	msg = append(msg, target...)
+
+	pointer := []byte{0xC0, byte(targetOff)}
+	pointerOff := len(msg)
+	msg = append(msg, pointer...)
+
+	name, off, ok := decodeName(msg, pointerOff)
+	if !ok {
+		t.Fatal("decodeName failed to follow compression pointer")
+	}
+	if name != "myhost.local." {
+		t.Errorf("decodeName = %q, want %q", name, "myhost.local.")
+	}
+	if off != pointerOff+2 {
+		t.Errorf("decodeName returned offset %d, want %d (just past the 2-byte pointer)", off, pointerOff+2)
+	}
+}
+
+func TestDecodeNameRejectsPointerLoop(t *testing.T) {
+	// A pointer at offset 12 that points to itself must not spin forever -
+	// decodeName bounds its jump count and reports failure instead.
+	msg := make([]byte, 14)
+	msg[12] = 0xC0
+	msg[13] = 12
+
+	if _, _, ok := decodeName(msg, 12); ok {
+		t.Fatal("decodeName succeeded on a self-referential pointer loop, want failure")
+	}
+}
+
+func TestDecodeNameRejectsTruncatedMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  []byte
+		off  int
+	}{
+		{"offset past end of message", []byte{0x00}, 5},
+		{"label length overruns buffer", []byte{0x10, 'a', 'b'}, 0},
+		{"pointer with no second byte", []byte{0xC0}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, ok := decodeName(tt.msg, tt.off); ok {
+				t.Errorf("decodeName(%v, %d) succeeded, want failure", tt.msg, tt.off)
+			}
+		})
+	}
+}
+
+func TestDecodeTXT(t *testing.T) {
+	tests := []struct {
+		name  string
+		rdata []byte
+		want  map[string]string
+	}{
+		{
+			name:  "key=value pairs",
+			rdata: append([]byte{byte(len("model=foo"))}, "model=foo"...),
+			want:  map[string]string{"model": "foo"},
+		},
+		{
+			name:  "bare flag with no value",
+			rdata: append([]byte{byte(len("tls"))}, "tls"...),
+			want:  map[string]string{"tls": ""},
+		},
+		{
+			name:  "multiple entries",
+			rdata: append(append([]byte{byte(len("a=1"))}, "a=1"...), append([]byte{byte(len("b=2"))}, "b=2"...)...),
+			want:  map[string]string{"a": "1", "b": "2"},
+		},
+		{
+			name:  "empty rdata",
+			rdata: []byte{},
+			want:  map[string]string{},
+		},
+		{
+			name:  "truncated length prefix stops cleanly instead of panicking",
+			rdata: []byte{10, 'a', 'b'},
+			want:  map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeTXT(tt.rdata)
+			if len(got) != len(tt.want) {
+				t.Fatalf("decodeTXT(%v) = %v, want %v", tt.rdata, got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("decodeTXT(%v)[%q] = %q, want %q", tt.rdata, k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceName(t *testing.T) {
+	tests := []struct {
+		serviceType string
+		want        string
+	}{
+		{"_ssh._tcp.local.", "SSH"},
+		{"_googlecast._tcp.local.", "GOOGLECAST"},
+		{"_airplay._tcp.local.", "AIRPLAY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.serviceType, func(t *testing.T) {
+			if got := serviceName(tt.serviceType); got != tt.want {
+				t.Errorf("serviceName(%q) = %q, want %q", tt.serviceType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMDNSMessageEmptyAndTruncatedInput(t *testing.T) {
+	if recs := parseMDNSMessage(nil); recs != nil {
+		t.Errorf("parseMDNSMessage(nil) = %v, want nil", recs)
+	}
+	if recs := parseMDNSMessage(make([]byte, 5)); recs != nil {
+		t.Errorf("parseMDNSMessage(5 bytes) = %v, want nil", recs)
+	}
+}
+
+// buildPTRSRVAMessage assembles a minimal mDNS response announcing one
+// instance of _ssh._tcp.local. at myhost.local:22 with IP 10.0.0.5, using
+// the same record encoding real responses use but with every name written
+// uncompressed (compression pointers are covered by
+// TestDecodeNameFollowsCompressionPointer).
+func buildPTRSRVAMessage(t *testing.T) []byte {
+	t.Helper()
+
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg[6:8], 3) // ANCOUNT = 3 (PTR, SRV, A)
+
+	// PTR: _ssh._tcp.local. -> myhost._ssh._tcp.local.
+	ptrOwnerOff := len(msg)
+	msg = append(msg, encodeName("_ssh._tcp.local.")...)
+	instanceName := "myhost._ssh._tcp.local."
+	msg = appendRR(msg, ptrOwnerOff, dnsTypePTR, encodeName(instanceName))
+
+	// SRV: myhost._ssh._tcp.local. -> target myhost.local., port 22.
+	// Both the owner name and the target are written as fresh label
+	// sequences rather than compression pointers, to keep the test
+	// self-contained.
+	srvOwnerOff := len(msg)
+	msg = append(msg, encodeName(instanceName)...)
+	srvRdata := make([]byte, 6)
+	binary.BigEndian.PutUint16(srvRdata[4:6], 22)
+	srvRdata = append(srvRdata, encodeName("myhost.local.")...)
+	msg = appendRR(msg, srvOwnerOff, dnsTypeSRV, srvRdata)
+
+	// A: myhost.local. -> 10.0.0.5
+	aOwnerOff := len(msg)
+	msg = append(msg, encodeName("myhost.local.")...)
+	msg = appendRR(msg, aOwnerOff, dnsTypeA, []byte{10, 0, 0, 5})
+
+	return msg
+}
+
+// appendRR appends TYPE/CLASS/TTL/RDLENGTH/RDATA for a record whose owner
+// name has already been written at ownerOff (ownerOff is unused here but
+// documents intent for readers matching this against decodeName's offset
+// tracking).
+func appendRR(msg []byte, ownerOff int, rrType uint16, rdata []byte) []byte {
+	head := make([]byte, 10) // TYPE(2) + CLASS(2) + TTL(4) + RDLENGTH(2)
+	binary.BigEndian.PutUint16(head[0:2], rrType)
+	binary.BigEndian.PutUint16(head[2:4], dnsClassIN)
+	// TTL left as zero.
+	binary.BigEndian.PutUint16(head[8:10], uint16(len(rdata)))
+	msg = append(msg, head...)
+	msg = append(msg, rdata...)
+	return msg
+}
+
+func TestParseMDNSMessageAssemblesRecordAcrossPTRSRVA(t *testing.T) {
+	msg := buildPTRSRVAMessage(t)
+
+	recs := parseMDNSMessage(msg)
+	if len(recs) != 1 {
+		t.Fatalf("parseMDNSMessage returned %d records, want 1: %+v", len(recs), recs)
+	}
+
+	rec := recs[0]
+	if rec.serviceType != "_ssh._tcp.local." {
+		t.Errorf("serviceType = %q, want %q", rec.serviceType, "_ssh._tcp.local.")
+	}
+	if rec.instance != "myhost._ssh._tcp.local." {
+		t.Errorf("instance = %q, want %q", rec.instance, "myhost._ssh._tcp.local.")
+	}
+	if rec.port != 22 {
+		t.Errorf("port = %d, want 22", rec.port)
+	}
+	if rec.target != "myhost.local." {
+		t.Errorf("target = %q, want %q", rec.target, "myhost.local.")
+	}
+	if len(rec.ips) != 1 || rec.ips[0] != "10.0.0.5" {
+		t.Errorf("ips = %v, want [10.0.0.5]", rec.ips)
+	}
+}
+
+func TestParseMDNSMessageTruncatedRecordIsSkippedNotPanicked(t *testing.T) {
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg[6:8], 1) // ANCOUNT = 1, but no record follows
+	msg = append(msg, encodeName("_ssh._tcp.local.")...)
+	// Missing TYPE/CLASS/TTL/RDLENGTH/RDATA entirely.
+
+	recs := parseMDNSMessage(msg)
+	if len(recs) != 0 {
+		t.Errorf("parseMDNSMessage on a truncated record = %v, want no records", recs)
+	}
+}
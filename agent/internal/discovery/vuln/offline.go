@@ -0,0 +1,335 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OfflineDatabase answers Lookup from local feeds: NVD JSON data feeds
+// (https://nvd.nist.gov/vuln/data-feeds) under <dir>/nvd/*.json, and
+// endoflife.date product JSON (https://endoflife.date/docs/api) under
+// <dir>/eol/<product>.json. No network access.
+type OfflineDatabase struct {
+	mu     sync.RWMutex
+	cves   map[string][]cveRecord
+	cycles map[string][]eolCycle
+}
+
+// NewOfflineDatabase loads every feed file under dir. Missing subdirectories
+// are treated as "no data of that kind", not an error.
+func NewOfflineDatabase(dir string) (*OfflineDatabase, error) {
+	db := &OfflineDatabase{
+		cves:   make(map[string][]cveRecord),
+		cycles: make(map[string][]eolCycle),
+	}
+
+	if err := db.loadNVD(filepath.Join(dir, "nvd")); err != nil {
+		return nil, fmt.Errorf("load nvd feeds: %w", err)
+	}
+	if err := db.loadEOL(filepath.Join(dir, "eol")); err != nil {
+		return nil, fmt.Errorf("load eol data: %w", err)
+	}
+
+	return db, nil
+}
+
+func (db *OfflineDatabase) loadNVD(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		var feed nvdFeed
+		if err := json.Unmarshal(data, &feed); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		db.indexFeed(feed)
+	}
+	return nil
+}
+
+func (db *OfflineDatabase) indexFeed(feed nvdFeed) {
+	for _, item := range feed.CVEItems {
+		rec := cveRecord{
+			id:       item.CVE.CVEDataMeta.ID,
+			cvss:     item.Impact.BaseMetricV3.CVSSV3.BaseScore,
+			severity: item.Impact.BaseMetricV3.CVSSV3.BaseSeverity,
+		}
+		if descs := item.CVE.Description.DescriptionData; len(descs) > 0 {
+			rec.summary = descs[0].Value
+		}
+
+		seen := make(map[string]bool)
+		for _, node := range item.Configurations.Nodes {
+			for _, match := range node.CPEMatch {
+				product, version, ok := parseCPE23(match.CPE23URI)
+				if !ok || seen[product] {
+					continue
+				}
+				seen[product] = true
+
+				r := rec
+				r.version = version
+				r.versionStartIncluding = match.VersionStartIncluding
+				r.versionEndExcluding = match.VersionEndExcluding
+				db.cves[product] = append(db.cves[product], r)
+			}
+		}
+	}
+}
+
+func (db *OfflineDatabase) loadEOL(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		var cycles []eolCycle
+		if err := json.Unmarshal(data, &cycles); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		product := slugify(strings.TrimSuffix(entry.Name(), ".json"))
+		db.cycles[product] = cycles
+	}
+	return nil
+}
+
+func (db *OfflineDatabase) Lookup(ctx context.Context, product, version string) (*Assessment, error) {
+	// Build the CPE 2.3 string for this product/version and parse it back
+	// apart with parseCPE23, the same parser used to index the NVD feeds;
+	// buildCPE23 slugs the product and uses "-" for an empty version.
+	slug, queryVersion, ok := parseCPE23(buildCPE23(product, version))
+	if !ok {
+		return nil, nil
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var a Assessment
+	for _, rec := range db.cves[slug] {
+		if !rec.matches(queryVersion) {
+			continue
+		}
+		a.CVEs = append(a.CVEs, CVEMatch{
+			ID:       rec.id,
+			Severity: rec.severity,
+			CVSS:     rec.cvss,
+			Summary:  rec.summary,
+		})
+	}
+
+	if cycles, ok := db.cycles[slug]; ok {
+		a.EndOfLife = eolForVersion(cycles, queryVersion)
+	}
+
+	if len(a.CVEs) == 0 && a.EndOfLife == nil {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+// nvdFeed is the subset of an NVD CVE data feed JSON file we need.
+type nvdFeed struct {
+	CVEItems []struct {
+		CVE struct {
+			CVEDataMeta struct {
+				ID string `json:"ID"`
+			} `json:"CVE_data_meta"`
+			Description struct {
+				DescriptionData []struct {
+					Value string `json:"value"`
+				} `json:"description_data"`
+			} `json:"description"`
+		} `json:"cve"`
+		Configurations struct {
+			Nodes []struct {
+				CPEMatch []struct {
+					CPE23URI              string `json:"cpe23Uri"`
+					VersionStartIncluding string `json:"versionStartIncluding,omitempty"`
+					VersionEndExcluding   string `json:"versionEndExcluding,omitempty"`
+				} `json:"cpe_match"`
+			} `json:"nodes"`
+		} `json:"configurations"`
+		Impact struct {
+			BaseMetricV3 struct {
+				CVSSV3 struct {
+					BaseScore    float64 `json:"baseScore"`
+					BaseSeverity string  `json:"baseSeverity"`
+				} `json:"cvssV3"`
+			} `json:"baseMetricV3"`
+		} `json:"impact"`
+	} `json:"CVE_Items"`
+}
+
+// cveRecord is one NVD entry indexed by CPE product name.
+type cveRecord struct {
+	id                    string
+	cvss                  float64
+	severity              string
+	summary               string
+	version               string // exact version this entry pins to, if any
+	versionStartIncluding string
+	versionEndExcluding   string
+}
+
+func (r cveRecord) matches(version string) bool {
+	if version == "" {
+		return false
+	}
+	if r.version != "" && r.version != "*" && r.version != "-" {
+		return r.version == version
+	}
+	if r.versionStartIncluding != "" && compareVersions(version, r.versionStartIncluding) < 0 {
+		return false
+	}
+	if r.versionEndExcluding != "" && compareVersions(version, r.versionEndExcluding) >= 0 {
+		return false
+	}
+	return r.versionStartIncluding != "" || r.versionEndExcluding != ""
+}
+
+// eolCycle is one entry of an endoflife.date product JSON array.
+type eolCycle struct {
+	Cycle string  `json:"cycle"`
+	EOL   eolDate `json:"eol"`
+}
+
+// eolDate unmarshals either a YYYY-MM-DD string (an announced end-of-life
+// date) or the literal `false` endoflife.date uses when none has been
+// announced yet.
+type eolDate struct {
+	t     time.Time
+	valid bool
+}
+
+func (d *eolDate) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil // `false`, `true`, or a malformed value - treat as unset
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil
+	}
+	d.t, d.valid = t, true
+	return nil
+}
+
+func eolForVersion(cycles []eolCycle, version string) *time.Time {
+	major := majorMinor(version)
+	for _, c := range cycles {
+		if c.Cycle == version || c.Cycle == major {
+			if !c.EOL.valid {
+				return nil
+			}
+			t := c.EOL.t
+			return &t
+		}
+	}
+	return nil
+}
+
+// parseCPE23 extracts the product and version fields from a CPE 2.3 URI
+// like "cpe:2.3:a:openbsd:openssh:8.9:p1:*:*:*:*:*:*", returning ok=false
+// for anything that isn't an application (part "a") entry.
+func parseCPE23(uri string) (product, version string, ok bool) {
+	parts := strings.Split(uri, ":")
+	if len(parts) < 6 || parts[0] != "cpe" || parts[1] != "2.3" || parts[2] != "a" {
+		return "", "", false
+	}
+	return parts[4], parts[5], true
+}
+
+// slugify normalizes a product name the same way buildCPE23 does, so
+// lookups match however the caller capitalized/spaced the product string.
+func slugify(product string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(product), " ", "_"))
+}
+
+// buildCPE23 constructs the "any vendor" CPE 2.3 string used to query the
+// NVD backend: vendor is left as a wildcard since ServiceInfo
+// doesn't carry one.
+func buildCPE23(product, version string) string {
+	p := slugify(product)
+	v := strings.TrimSpace(version)
+	if v == "" {
+		v = "-"
+	}
+	return fmt.Sprintf("cpe:2.3:a:*:%s:%s:*:*:*:*:*:*:*", p, v)
+}
+
+// majorMinor returns the "X.Y" prefix of a dotted version string, which is
+// how endoflife.date names most of its cycles.
+func majorMinor(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// compareVersions does a best-effort numeric comparison of dotted version
+// strings (ignoring any trailing non-numeric suffix per component), since
+// versions in the wild don't reliably follow semver.
+func compareVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var an, bn int
+		if i < len(as) {
+			an = leadingInt(as[i])
+		}
+		if i < len(bs) {
+			bn = leadingInt(bs[i])
+		}
+		if an != bn {
+			if an < bn {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func leadingInt(s string) int {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	n, _ := strconv.Atoi(s[:end])
+	return n
+}
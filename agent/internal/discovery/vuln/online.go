@@ -0,0 +1,248 @@
+package vuln
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const osvQueryURL = "https://api.osv.dev/v1/query"
+
+// productEcosystem maps a detected product name to the OSV.dev ecosystem
+// its advisories are filed under. Products with no entry fall back to
+// ecosystemGeneric, which OSV accepts as "search by name across all
+// ecosystems" (an empty ecosystem field in the query).
+var productEcosystem = map[string]string{
+	"openssh":    "Debian",
+	"nginx":      "Debian",
+	"mysql":      "Debian",
+	"mariadb":    "Debian",
+	"postgresql": "Debian",
+	"redis":      "Debian",
+	"apache":     "Debian",
+}
+
+const ecosystemGeneric = ""
+
+// OSVDatabase answers Lookup by querying OSV.dev's REST API, rate limited
+// and cached in memory so a single discovery scan with many hosts running
+// the same handful of products doesn't hammer the API.
+type OSVDatabase struct {
+	client  *http.Client
+	limiter *rateLimiter
+	cache   *lruCache
+}
+
+// NewOSVDatabase builds an OSVDatabase that allows at most maxPerSecond
+// requests/sec and caches up to cacheSize product/version lookups.
+func NewOSVDatabase(maxPerSecond int, cacheSize int) *OSVDatabase {
+	return &OSVDatabase{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		limiter: newRateLimiter(maxPerSecond),
+		cache:   newLRUCache(cacheSize),
+	}
+}
+
+type osvQuery struct {
+	Package osvPackage `json:"package"`
+	Version string     `json:"version,omitempty"`
+}
+
+type osvPackage struct {
+	Ecosystem string `json:"ecosystem,omitempty"`
+	Name      string `json:"name"`
+}
+
+type osvResponse struct {
+	Vulns []osvVuln `json:"vulns"`
+}
+
+type osvVuln struct {
+	ID       string `json:"id"`
+	Summary  string `json:"summary"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+func (db *OSVDatabase) Lookup(ctx context.Context, product, version string) (*Assessment, error) {
+	key := product + "@" + version
+	if cached, ok := db.cache.get(key); ok {
+		return cached, nil
+	}
+
+	if err := db.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	query := osvQuery{
+		Package: osvPackage{
+			Ecosystem: productEcosystem[slugify(product)],
+			Name:      product,
+		},
+		Version: version,
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, osvQueryURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := db.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("osv.dev query: unexpected status %s", resp.Status)
+	}
+
+	var out osvResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("osv.dev query: decode response: %w", err)
+	}
+
+	if len(out.Vulns) == 0 {
+		db.cache.put(key, nil)
+		return nil, nil
+	}
+
+	a := &Assessment{}
+	for _, v := range out.Vulns {
+		severity := v.DatabaseSpecific.Severity
+		if severity == "" {
+			severity = "UNKNOWN"
+		}
+		a.CVEs = append(a.CVEs, CVEMatch{
+			ID:       v.ID,
+			Severity: severity,
+			CVSS:     cvssScore(v),
+			Summary:  v.Summary,
+		})
+	}
+
+	db.cache.put(key, a)
+	return a, nil
+}
+
+// cvssScore extracts a numeric base score for v so vuln.Score can weigh it,
+// preferring a directly numeric severity[].score and falling back to a
+// band derived from database_specific.severity (the label OSV mirrors from
+// upstream trackers like Debian's) since severity[].score is usually a
+// CVSS vector string rather than a parsed score.
+func cvssScore(v osvVuln) float64 {
+	for _, s := range v.Severity {
+		if score, err := strconv.ParseFloat(s.Score, 64); err == nil {
+			return score
+		}
+	}
+	return severityBand(v.DatabaseSpecific.Severity)
+}
+
+// severityBand maps an upstream severity label to a representative CVSS
+// base score, in roughly the same band vuln.Score's thresholds expect.
+func severityBand(label string) float64 {
+	switch strings.ToUpper(label) {
+	case "CRITICAL":
+		return 9.5
+	case "HIGH", "IMPORTANT":
+		return 8.0
+	case "MEDIUM", "MODERATE":
+		return 5.5
+	case "LOW":
+		return 2.0
+	default:
+		return 0
+	}
+}
+
+// rateLimiter is a minimal token bucket: it refills one slot every
+// 1/perSecond and blocks callers until a slot is available or ctx is done.
+type rateLimiter struct {
+	ticker *time.Ticker
+	tokens chan struct{}
+}
+
+func newRateLimiter(perSecond int) *rateLimiter {
+	if perSecond <= 0 {
+		perSecond = 1
+	}
+	rl := &rateLimiter{
+		ticker: time.NewTicker(time.Second / time.Duration(perSecond)),
+		tokens: make(chan struct{}, 1),
+	}
+	rl.tokens <- struct{}{}
+	go func() {
+		for range rl.ticker.C {
+			select {
+			case rl.tokens <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return rl
+}
+
+func (rl *rateLimiter) wait(ctx context.Context) error {
+	select {
+	case <-rl.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// lruCache is a small fixed-size cache with FIFO eviction (an approximation
+// of LRU that avoids pulling in container/list bookkeeping for what's
+// expected to be a few dozen distinct product/version pairs per scan).
+type lruCache struct {
+	mu      sync.Mutex
+	size    int
+	order   []string
+	entries map[string]*Assessment
+}
+
+func newLRUCache(size int) *lruCache {
+	if size <= 0 {
+		size = 256
+	}
+	return &lruCache{size: size, entries: make(map[string]*Assessment)}
+}
+
+func (c *lruCache) get(key string) (*Assessment, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	a, ok := c.entries[key]
+	return a, ok
+}
+
+func (c *lruCache) put(key string, a *Assessment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists {
+		if len(c.order) >= c.size {
+			oldest := c.order[0]
+			c.order = c.order[1:]
+			delete(c.entries, oldest)
+		}
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = a
+}
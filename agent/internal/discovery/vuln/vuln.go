@@ -0,0 +1,68 @@
+// Package vuln cross-references detected service products/versions against
+// a vulnerability and end-of-life database to produce a migration risk
+// signal, independent of the discovery package's host model so it can be
+// reused without import cycles.
+package vuln
+
+import (
+	"context"
+	"time"
+)
+
+// CVEMatch is one vulnerability found for a product/version pair.
+type CVEMatch struct {
+	ID       string  `json:"id"`
+	Severity string  `json:"severity"` // the source's label, e.g. LOW, MEDIUM, HIGH, CRITICAL; UNKNOWN when OSV gives none
+	CVSS     float64 `json:"cvss,omitempty"`
+	Summary  string  `json:"summary,omitempty"`
+}
+
+// Assessment is what a Database reports for one product/version lookup.
+type Assessment struct {
+	CVEs      []CVEMatch
+	EndOfLife *time.Time
+}
+
+// Database answers "what's wrong with running this version" queries. The
+// offline backend (OfflineDatabase) reads local NVD/endoflife.date feeds;
+// the online backend (OSVDatabase) queries OSV.dev.
+type Database interface {
+	// Lookup returns nil, nil when nothing is known about product/version -
+	// that's the common case and callers shouldn't treat it as an error.
+	Lookup(ctx context.Context, product, version string) (*Assessment, error)
+}
+
+// Score rolls an Assessment up into a single 0-100 migration risk signal,
+// weighting CVSS severity, the count of critical CVEs, and EOL status.
+func Score(a *Assessment) int {
+	if a == nil {
+		return 0
+	}
+
+	score := 0
+	critical := 0
+	for _, c := range a.CVEs {
+		switch {
+		case c.CVSS >= 9.0:
+			score += 25
+			critical++
+		case c.CVSS >= 7.0:
+			score += 15
+		case c.CVSS >= 4.0:
+			score += 7
+		default:
+			score += 2
+		}
+	}
+	if critical >= 2 {
+		score += 15
+	}
+	if a.EndOfLife != nil && a.EndOfLife.Before(time.Now()) {
+		score += 30
+	}
+
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
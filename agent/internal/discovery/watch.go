@@ -0,0 +1,299 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Event signals that something changed about a piece of network state (a VM
+// was created, a security-group rule was added, a new DHCP lease or ARP
+// entry was seen) and that the affected address(es) deserve a targeted
+// rescan. Exactly one of IP or CIDR should be set.
+type Event struct {
+	IP   string
+	CIDR string
+}
+
+// targets expands the event to the list of IPs a rescan should cover.
+func (e Event) targets() []string {
+	if e.IP != "" {
+		return []string{e.IP}
+	}
+	if e.CIDR != "" {
+		ips, err := expandCIDR(e.CIDR)
+		if err == nil {
+			return ips
+		}
+	}
+	return nil
+}
+
+// EventSource feeds infrastructure-change events to Watch. Implementations
+// must close their Events channel once Close is called or the underlying
+// transport is exhausted.
+type EventSource interface {
+	Events() <-chan Event
+	Close() error
+}
+
+// NoopEventSource never produces events. It exists so tests (and callers
+// that just want the debounced-rescan machinery without a real event feed)
+// can use Watch without standing up a broker.
+type NoopEventSource struct {
+	events chan Event
+}
+
+// NewNoopEventSource returns an EventSource that never emits.
+func NewNoopEventSource() *NoopEventSource {
+	return &NoopEventSource{events: make(chan Event)}
+}
+
+func (n *NoopEventSource) Events() <-chan Event { return n.events }
+func (n *NoopEventSource) Close() error         { close(n.events); return nil }
+
+// Watch consumes events from source and performs targeted rescans of just
+// the affected IPs, emitting delta DiscoveryResults on the returned channel.
+// Results are deduped against baseline (and subsequent deltas) so unchanged
+// hosts are never re-emitted, and bursts of events against the same IP
+// within debounce are coalesced into a single rescan.
+//
+// The returned channel is closed once ctx is cancelled or source.Events()
+// closes.
+func (s *Scanner) Watch(ctx context.Context, baseline *DiscoveryResult, source EventSource, debounce time.Duration) (<-chan *DiscoveryResult, error) {
+	if source == nil {
+		return nil, fmt.Errorf("event source is required")
+	}
+	if debounce <= 0 {
+		debounce = 5 * time.Second
+	}
+
+	known := make(map[string]DiscoveredHost)
+	if baseline != nil {
+		for _, h := range baseline.Hosts {
+			known[h.IP] = h
+		}
+	}
+
+	out := make(chan *DiscoveryResult, 16)
+
+	go func() {
+		defer close(out)
+
+		var mu sync.Mutex
+		pending := make(map[string]*time.Timer)
+
+		rescan := func(ip string) {
+			host := s.scanHost(ctx, ip)
+			if host == nil {
+				return
+			}
+
+			mu.Lock()
+			prev, existed := known[ip]
+			unchanged := existed && hostsEqual(prev, *host)
+			known[ip] = *host
+			mu.Unlock()
+
+			if unchanged {
+				return
+			}
+
+			delta := &DiscoveryResult{
+				ScanID:      fmt.Sprintf("watch-%d", time.Now().UnixNano()),
+				StartedAt:   time.Now(),
+				CompletedAt: time.Now(),
+				Hosts:       []DiscoveredHost{*host},
+				Summary: DiscoverySummary{
+					TotalHosts: 1,
+					ByCategory: map[string]int{host.Category: 1},
+					ByOS:       map[string]int{host.OS: 1},
+				},
+			}
+			if host.Status == "online" {
+				delta.Summary.OnlineHosts = 1
+			}
+
+			select {
+			case out <- delta:
+			case <-ctx.Done():
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-source.Events():
+				if !ok {
+					return
+				}
+				for _, ip := range ev.targets() {
+					ip := ip
+					mu.Lock()
+					if t, scheduled := pending[ip]; scheduled {
+						t.Stop()
+					}
+					pending[ip] = time.AfterFunc(debounce, func() {
+						mu.Lock()
+						delete(pending, ip)
+						mu.Unlock()
+						rescan(ip)
+					})
+					mu.Unlock()
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// hostsEqual reports whether two DiscoveredHost snapshots are equivalent
+// for the purpose of deciding whether a delta is worth emitting. LastSeen is
+// intentionally excluded since it always differs between rescans, and
+// OpenPorts/Services are sorted first since scanHost appends them in
+// goroutine-completion order, which varies between otherwise-identical
+// scans.
+func hostsEqual(a, b DiscoveredHost) bool {
+	a.LastSeen = time.Time{}
+	b.LastSeen = time.Time{}
+	a.ResponseTime = 0
+	b.ResponseTime = 0
+
+	sortHostPortsAndServices(&a)
+	sortHostPortsAndServices(&b)
+
+	aj, errA := json.Marshal(a)
+	bj, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return string(aj) == string(bj)
+}
+
+// sortHostPortsAndServices orders h.OpenPorts and h.Services by port (then
+// name, for services sharing a port) so two scans of the same host compare
+// equal regardless of the order their probes happened to complete in.
+func sortHostPortsAndServices(h *DiscoveredHost) {
+	sort.Slice(h.OpenPorts, func(i, j int) bool {
+		return h.OpenPorts[i].Port < h.OpenPorts[j].Port
+	})
+	sort.Slice(h.Services, func(i, j int) bool {
+		if h.Services[i].Port != h.Services[j].Port {
+			return h.Services[i].Port < h.Services[j].Port
+		}
+		return h.Services[i].Name < h.Services[j].Name
+	})
+}
+
+// ipOrCIDREventPattern extracts an IPv4 address or CIDR block from a
+// syslog/log line for FileTailEventSource.
+var ipOrCIDREventPattern = regexp.MustCompile(`\b(\d{1,3}(?:\.\d{1,3}){3}(?:/\d{1,2})?)\b`)
+
+// FileTailEventSource tails a local file (a syslog output, a DHCP lease
+// log, ...) and emits an Event for the first IP/CIDR it finds in each new
+// line. Events are dropped while the 64-entry buffer is full.
+type FileTailEventSource struct {
+	events chan Event
+	stop   chan struct{}
+}
+
+// NewFileTailEventSource opens path, seeks to its current end (so only new
+// lines produce events), and starts tailing it on a short polling interval.
+func NewFileTailEventSource(path string, pollInterval time.Duration) (*FileTailEventSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("seek %s: %w", path, err)
+	}
+	if pollInterval <= 0 {
+		pollInterval = 1 * time.Second
+	}
+
+	src := &FileTailEventSource{
+		events: make(chan Event, 64),
+		stop:   make(chan struct{}),
+	}
+
+	go src.run(f, pollInterval)
+	return src, nil
+}
+
+func (s *FileTailEventSource) run(f *os.File, pollInterval time.Duration) {
+	defer f.Close()
+	defer close(s.events)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	buf := make([]byte, 0, 4096)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			chunk := make([]byte, 4096)
+			n, err := f.Read(chunk)
+			if n > 0 {
+				buf = append(buf, chunk[:n]...)
+				for {
+					idx := indexByte(buf, '\n')
+					if idx < 0 {
+						break
+					}
+					line := string(buf[:idx])
+					buf = buf[idx+1:]
+					s.emitFromLine(line)
+				}
+			}
+			if err != nil && err != io.EOF {
+				return
+			}
+		}
+	}
+}
+
+func (s *FileTailEventSource) emitFromLine(line string) {
+	m := ipOrCIDREventPattern.FindStringSubmatch(line)
+	if len(m) == 0 {
+		return
+	}
+	addr := m[1]
+	ev := Event{IP: addr}
+	if ip, _, err := net.ParseCIDR(addr); err == nil {
+		ev = Event{CIDR: addr}
+		_ = ip
+	}
+	select {
+	case s.events <- ev:
+	default:
+		// Drop the event rather than block the tailer if the consumer is slow.
+	}
+}
+
+func indexByte(b []byte, c byte) int {
+	for i, v := range b {
+		if v == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *FileTailEventSource) Events() <-chan Event { return s.events }
+
+func (s *FileTailEventSource) Close() error {
+	close(s.stop)
+	return nil
+}
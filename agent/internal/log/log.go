@@ -0,0 +1,191 @@
+// Package log provides leveled, structured logging with two selectable
+// backends: a colorized line format for an interactive terminal, and a
+// JSON-lines format suitable for shipping to Loki/CloudWatch when the
+// agent runs unattended (systemd, Kubernetes). Callers attach contextual
+// fields (cmd_id, cmd_type, path, network, ...) rather than interpolating
+// them into the message, so either backend can render or index them.
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// Level orders log severity from least to most urgent.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Field is one piece of structured context attached to a log line.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F builds a Field; short name since call sites pass several per line.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Logger writes leveled, structured log lines. With returns a child logger
+// that carries the given fields in addition to (not instead of) its parent's.
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	With(fields ...Field) Logger
+}
+
+// New returns a Logger writing to w using the named backend: "json" for
+// JSON lines, "tty" for colorized text, or "" to auto-select TTY when w is
+// an interactive terminal and JSON otherwise.
+func New(backend string, w io.Writer) Logger {
+	switch backend {
+	case "json":
+		return &jsonLogger{w: w}
+	case "tty":
+		return &ttyLogger{w: w}
+	default:
+		if f, ok := w.(*os.File); ok && isatty.IsTerminal(f.Fd()) {
+			return &ttyLogger{w: w}
+		}
+		return &jsonLogger{w: w}
+	}
+}
+
+// NewFromEnv behaves like New, taking the backend name from
+// CLOUDMIGRATE_LOG_FORMAT (values: "json", "tty", or unset for
+// auto-detection).
+func NewFromEnv(w io.Writer) Logger {
+	return New(os.Getenv("CLOUDMIGRATE_LOG_FORMAT"), w)
+}
+
+var defaultLogger = NewFromEnv(os.Stdout)
+
+// Default returns the process-wide logger used when no request-scoped
+// logger is available (see FromContext).
+func Default() Logger { return defaultLogger }
+
+// SetDefault replaces the process-wide logger, e.g. so main can install one
+// with a different backend or writer.
+func SetDefault(l Logger) { defaultLogger = l }
+
+// jsonLogger emits one JSON object per line:
+// {"ts":...,"level":...,"msg":...,<fields...>}.
+type jsonLogger struct {
+	w      io.Writer
+	fields []Field
+}
+
+func (l *jsonLogger) log(level Level, msg string, fields ...Field) {
+	line := make(map[string]interface{}, len(l.fields)+len(fields)+3)
+	line["ts"] = time.Now().UTC().Format(time.RFC3339Nano)
+	line["level"] = level.String()
+	line["msg"] = msg
+	for _, f := range l.fields {
+		line[f.Key] = f.Value
+	}
+	for _, f := range fields {
+		line[f.Key] = f.Value
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(l.w, string(data))
+}
+
+func (l *jsonLogger) Debug(msg string, fields ...Field) { l.log(LevelDebug, msg, fields...) }
+func (l *jsonLogger) Info(msg string, fields ...Field)  { l.log(LevelInfo, msg, fields...) }
+func (l *jsonLogger) Warn(msg string, fields ...Field)  { l.log(LevelWarn, msg, fields...) }
+func (l *jsonLogger) Error(msg string, fields ...Field) { l.log(LevelError, msg, fields...) }
+
+func (l *jsonLogger) With(fields ...Field) Logger {
+	return &jsonLogger{w: l.w, fields: append(append([]Field{}, l.fields...), fields...)}
+}
+
+// ttyLogger keeps today's colorized single-line UX, appending fields as
+// trailing "key=value" pairs.
+type ttyLogger struct {
+	w      io.Writer
+	fields []Field
+}
+
+func (l *ttyLogger) render(msg string, fields []Field) string {
+	all := append(append([]Field{}, l.fields...), fields...)
+	if len(all) == 0 {
+		return msg
+	}
+	parts := make([]string, len(all))
+	for i, f := range all {
+		parts[i] = fmt.Sprintf("%s=%v", f.Key, f.Value)
+	}
+	return msg + "  " + strings.Join(parts, " ")
+}
+
+func (l *ttyLogger) Debug(msg string, fields ...Field) {
+	color.New(color.FgHiBlack).Fprintln(l.w, l.render(msg, fields))
+}
+
+func (l *ttyLogger) Info(msg string, fields ...Field) {
+	color.New(color.FgGreen).Fprintln(l.w, l.render(msg, fields))
+}
+
+func (l *ttyLogger) Warn(msg string, fields ...Field) {
+	color.New(color.FgYellow).Fprintln(l.w, l.render(msg, fields))
+}
+
+func (l *ttyLogger) Error(msg string, fields ...Field) {
+	color.New(color.FgRed).Fprintln(l.w, l.render(msg, fields))
+}
+
+func (l *ttyLogger) With(fields ...Field) Logger {
+	return &ttyLogger{w: l.w, fields: append(append([]Field{}, l.fields...), fields...)}
+}
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx carrying l, retrievable with
+// FromContext, so sub-operations (scanner, discovery, transfer) inherit
+// the same contextual fields as the request that started them.
+func WithContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the Logger attached to ctx by WithContext, or
+// Default() if none was attached.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(ctxKey{}).(Logger); ok {
+		return l
+	}
+	return Default()
+}
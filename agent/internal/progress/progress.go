@@ -0,0 +1,135 @@
+// Package progress provides a small pluggable progress-reporting interface
+// shared by scanner, discovery, and transfer so a long-running operation can
+// report how far along it is without committing to a particular UI: a
+// terminal bar for interactive use, a JSON-line stream for machine
+// consumption, or nothing at all.
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cheggaaa/pb/v3"
+)
+
+// Progress receives updates about an operation whose total size (a file
+// count, a byte count, a host count) is known up front.
+type Progress interface {
+	// Start announces the total amount of work, in whatever unit the
+	// caller is counting (bytes, files, hosts).
+	Start(total int64)
+	// Add reports n more units of work completed.
+	Add(n int64)
+	// Finish marks the operation done, regardless of how much of total
+	// was actually reported via Add.
+	Finish()
+}
+
+// Noop discards every update. It's the default when no reporter is
+// configured, mirroring the pattern of discovery.NoopEventSource.
+type Noop struct{}
+
+func (Noop) Start(int64) {}
+func (Noop) Add(int64)   {}
+func (Noop) Finish()     {}
+
+// Bar renders a terminal progress bar via github.com/cheggaaa/pb/v3.
+type Bar struct {
+	bar *pb.ProgressBar
+}
+
+// NewBar returns a Bar ready to use; call Start to begin rendering.
+func NewBar() *Bar { return &Bar{} }
+
+func (b *Bar) Start(total int64) {
+	b.bar = pb.New64(total)
+	b.bar.SetTemplateString(`{{counters . }} {{bar . }} {{percent . }} {{etime . }}`)
+	b.bar.Start()
+}
+
+func (b *Bar) Add(n int64) {
+	if b.bar != nil {
+		b.bar.Add64(n)
+	}
+}
+
+func (b *Bar) Finish() {
+	if b.bar != nil {
+		b.bar.Finish()
+	}
+}
+
+// JSONLine emits one JSON object per update to w, for callers that want to
+// consume progress programmatically (e.g. --output json).
+type JSONLine struct {
+	w              io.Writer
+	total, current int64
+}
+
+// NewJSONLine returns a JSONLine reporter writing to w.
+func NewJSONLine(w io.Writer) *JSONLine {
+	return &JSONLine{w: w}
+}
+
+func (j *JSONLine) Start(total int64) {
+	j.total = total
+	j.emit()
+}
+
+func (j *JSONLine) Add(n int64) {
+	j.current += n
+	j.emit()
+}
+
+func (j *JSONLine) Finish() {
+	j.current = j.total
+	j.emit()
+}
+
+func (j *JSONLine) emit() {
+	json.NewEncoder(j.w).Encode(map[string]interface{}{
+		"current": j.current,
+		"total":   j.total,
+	})
+}
+
+// FromFlags picks the Progress implementation matching the repo's
+// --silent/--no-progress/--output conventions: silent or non-text output
+// suppresses the bar (a bar drawn over JSON output would corrupt it),
+// noProgress forces Noop even for text output.
+func FromFlags(silent, noProgress bool, outputFormat string) Progress {
+	if silent || noProgress {
+		return Noop{}
+	}
+	if outputFormat != "" && outputFormat != "text" {
+		return NewJSONLine(os.Stderr)
+	}
+	return NewBar()
+}
+
+// WatchInterrupt installs a SIGINT/SIGTERM handler that calls p.Finish()
+// before the process exits, so an operation interrupted mid-bar doesn't
+// leave a half-drawn terminal progress bar behind. Call the returned stop
+// func (typically via defer) once the tracked operation completes normally.
+func WatchInterrupt(p Progress) (stop func()) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-sigCh:
+			p.Finish()
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		close(done)
+		signal.Stop(sigCh)
+	}
+}
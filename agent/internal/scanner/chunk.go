@@ -0,0 +1,138 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/bits"
+	"math/rand"
+	"os"
+)
+
+// ChunkRef identifies one content-defined chunk of a file: its byte range
+// and the SHA256 of its content, so the server can dedupe identical chunks
+// across files (and across agents) instead of re-uploading them.
+type ChunkRef struct {
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+	SHA256 string `json:"sha256"`
+}
+
+// ChunkOptions bounds the size of chunks ChunkFile produces. AvgSize must
+// be a power of two - it's used to derive how many low bits of the rolling
+// hash decide a chunk boundary.
+type ChunkOptions struct {
+	MinSize int64
+	AvgSize int64
+	MaxSize int64
+}
+
+// DefaultChunkOptions matches typical FastCDC guidance: small enough that
+// a single changed byte doesn't invalidate a whole large file, large enough
+// to keep the chunk index itself small.
+func DefaultChunkOptions() ChunkOptions {
+	return ChunkOptions{
+		MinSize: 2 * 1024 * 1024,
+		AvgSize: 4 * 1024 * 1024,
+		MaxSize: 8 * 1024 * 1024,
+	}
+}
+
+func (o *ChunkOptions) setDefaults() {
+	def := DefaultChunkOptions()
+	if o.MinSize <= 0 {
+		o.MinSize = def.MinSize
+	}
+	if o.AvgSize <= 0 {
+		o.AvgSize = def.AvgSize
+	}
+	if o.MaxSize <= 0 {
+		o.MaxSize = def.MaxSize
+	}
+}
+
+// gearTable is the Gear hashing lookup table used by ChunkFile's rolling
+// hash (one pseudo-random uint64 per byte value, as in FastCDC). It's
+// generated once from a fixed seed rather than hard-coded so the chunking
+// boundaries it produces are reproducible across runs and hosts, which
+// matters for dedup to actually work.
+var gearTable = newGearTable()
+
+func newGearTable() [256]uint64 {
+	var table [256]uint64
+	r := rand.New(rand.NewSource(0x67656172)) // "gear"
+	for i := range table {
+		table[i] = r.Uint64()
+	}
+	return table
+}
+
+// ChunkFile streams path through a Gear-hash rolling checksum (FastCDC
+// style) and returns the content-defined chunk boundaries it finds. A
+// boundary is declared once a chunk has grown past opts.MinSize and the
+// low bits of the rolling hash all equal 1 (the number of bits is
+// log2(opts.AvgSize), so boundaries occur on average every AvgSize bytes),
+// or unconditionally once a chunk reaches opts.MaxSize. The file is read
+// once through a fixed-size buffer, so memory use stays O(1) regardless of
+// file size.
+func ChunkFile(path string, opts ChunkOptions) ([]ChunkRef, error) {
+	opts.setDefaults()
+	if opts.AvgSize&(opts.AvgSize-1) != 0 {
+		return nil, fmt.Errorf("chunk avg size %d must be a power of two", opts.AvgSize)
+	}
+	maskBits := bits.TrailingZeros64(uint64(opts.AvgSize))
+	mask := uint64(1)<<uint(maskBits) - 1
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var chunks []ChunkRef
+	buf := make([]byte, 64*1024)
+
+	var (
+		offset   int64
+		chunkLen int64
+		rollHash uint64
+		hasher   = sha256.New()
+	)
+
+	finalize := func() {
+		if chunkLen == 0 {
+			return
+		}
+		chunks = append(chunks, ChunkRef{
+			Offset: offset,
+			Length: chunkLen,
+			SHA256: hex.EncodeToString(hasher.Sum(nil)),
+		})
+		offset += chunkLen
+		chunkLen = 0
+		rollHash = 0
+		hasher = sha256.New()
+	}
+
+	for {
+		n, readErr := f.Read(buf)
+		for i := 0; i < n; i++ {
+			b := buf[i]
+			hasher.Write(buf[i : i+1])
+			chunkLen++
+			rollHash = (rollHash << 1) + gearTable[b]
+
+			if chunkLen >= opts.MaxSize || (chunkLen >= opts.MinSize && rollHash&mask == mask) {
+				finalize()
+			}
+		}
+		if readErr == io.EOF {
+			finalize()
+			return chunks, nil
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("read %s: %w", path, readErr)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package scanner
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestFile writes deterministic pseudo-random content of the given
+// size to a new file under t.TempDir() and returns its path.
+func writeTestFile(t *testing.T, name string, size int, seed int64) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	data := make([]byte, size)
+	rand.New(rand.NewSource(seed)).Read(data)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write test file: %v", err)
+	}
+	return path
+}
+
+func smallChunkOptions() ChunkOptions {
+	return ChunkOptions{MinSize: 64, AvgSize: 128, MaxSize: 256}
+}
+
+func TestChunkFileCoversEntireFileWithNoGapsOrOverlap(t *testing.T) {
+	path := writeTestFile(t, "data.bin", 10000, 1)
+
+	chunks, err := ChunkFile(path, smallChunkOptions())
+	if err != nil {
+		t.Fatalf("ChunkFile: %v", err)
+	}
+	if len(chunks) == 0 {
+		t.Fatal("ChunkFile returned no chunks for a non-empty file")
+	}
+
+	var total int64
+	for i, c := range chunks {
+		if c.Offset != total {
+			t.Fatalf("chunk %d offset = %d, want %d (immediately after the previous chunk)", i, c.Offset, total)
+		}
+		if c.Length <= 0 {
+			t.Fatalf("chunk %d has non-positive length %d", i, c.Length)
+		}
+		total += c.Length
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != info.Size() {
+		t.Errorf("chunks cover %d bytes, want %d (file size)", total, info.Size())
+	}
+}
+
+func TestChunkFileRespectsMinAndMaxSize(t *testing.T) {
+	path := writeTestFile(t, "data.bin", 50000, 2)
+	opts := smallChunkOptions()
+
+	chunks, err := ChunkFile(path, opts)
+	if err != nil {
+		t.Fatalf("ChunkFile: %v", err)
+	}
+
+	for i, c := range chunks {
+		last := i == len(chunks)-1
+		if c.Length > opts.MaxSize {
+			t.Errorf("chunk %d length %d exceeds MaxSize %d", i, c.Length, opts.MaxSize)
+		}
+		// Only the final chunk may be shorter than MinSize - it's whatever
+		// is left over once the file runs out.
+		if !last && c.Length < opts.MinSize {
+			t.Errorf("non-final chunk %d length %d is below MinSize %d", i, c.Length, opts.MinSize)
+		}
+	}
+}
+
+func TestChunkFileIsDeterministic(t *testing.T) {
+	path := writeTestFile(t, "data.bin", 20000, 3)
+	opts := smallChunkOptions()
+
+	first, err := ChunkFile(path, opts)
+	if err != nil {
+		t.Fatalf("ChunkFile (first run): %v", err)
+	}
+	second, err := ChunkFile(path, opts)
+	if err != nil {
+		t.Fatalf("ChunkFile (second run): %v", err)
+	}
+
+	if len(first) != len(second) {
+		t.Fatalf("chunk count differs between runs: %d vs %d", len(first), len(second))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("chunk %d differs between runs: %+v vs %+v", i, first[i], second[i])
+		}
+	}
+}
+
+func TestChunkFileProducesIdenticalHashesForIdenticalRegions(t *testing.T) {
+	// A prefix shared between two files should chunk identically - this is
+	// the property content-defined chunking exists to provide: an edit
+	// past the shared prefix shouldn't change the chunks covering it.
+	shared := make([]byte, 30000)
+	rand.New(rand.NewSource(4)).Read(shared)
+
+	path1 := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(path1, shared, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tail := make([]byte, 5000)
+	rand.New(rand.NewSource(5)).Read(tail)
+	path2 := filepath.Join(t.TempDir(), "b.bin")
+	if err := os.WriteFile(path2, append(append([]byte{}, shared...), tail...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := smallChunkOptions()
+	chunks1, err := ChunkFile(path1, opts)
+	if err != nil {
+		t.Fatalf("ChunkFile(a.bin): %v", err)
+	}
+	chunks2, err := ChunkFile(path2, opts)
+	if err != nil {
+		t.Fatalf("ChunkFile(b.bin): %v", err)
+	}
+
+	// Every chunk boundary fully inside the shared prefix must match
+	// exactly, except possibly the very last chunk of path1 (which, in
+	// path2, keeps growing into the appended tail before its own boundary
+	// is found).
+	matched := 0
+	for i := 0; i < len(chunks1)-1 && i < len(chunks2); i++ {
+		if chunks1[i] != chunks2[i] {
+			t.Fatalf("chunk %d diverges within the shared prefix: %+v vs %+v", i, chunks1[i], chunks2[i])
+		}
+		matched++
+	}
+	if matched == 0 {
+		t.Fatal("expected at least one matching chunk across both files' shared prefix")
+	}
+}
+
+func TestChunkFileRejectsNonPowerOfTwoAvgSize(t *testing.T) {
+	path := writeTestFile(t, "data.bin", 100, 6)
+
+	_, err := ChunkFile(path, ChunkOptions{MinSize: 10, AvgSize: 100, MaxSize: 200})
+	if err == nil {
+		t.Fatal("ChunkFile succeeded with a non-power-of-two AvgSize, want an error")
+	}
+}
+
+func TestChunkFileEmptyFileProducesNoChunks(t *testing.T) {
+	path := writeTestFile(t, "empty.bin", 0, 7)
+
+	chunks, err := ChunkFile(path, smallChunkOptions())
+	if err != nil {
+		t.Fatalf("ChunkFile: %v", err)
+	}
+	if len(chunks) != 0 {
+		t.Errorf("ChunkFile on an empty file returned %d chunks, want 0", len(chunks))
+	}
+}
+
+func TestChunkFileMissingFile(t *testing.T) {
+	_, err := ChunkFile(filepath.Join(t.TempDir(), "does-not-exist.bin"), smallChunkOptions())
+	if err == nil {
+		t.Fatal("ChunkFile succeeded on a missing file, want an error")
+	}
+}
@@ -8,29 +8,62 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/cloudmigrate/agent/internal/log"
+	"github.com/cloudmigrate/agent/internal/progress"
 )
 
 // FileInfo represents a single file
 type FileInfo struct {
-	Name     string    `json:"name"`
-	Path     string    `json:"path"`
-	Size     int64     `json:"size"`
-	IsDir    bool      `json:"isDir"`
-	Modified time.Time `json:"modified"`
-	Hash     string    `json:"hash,omitempty"`
-	Ext      string    `json:"ext"`
+	Name     string     `json:"name"`
+	Path     string     `json:"path"`
+	Size     int64      `json:"size"`
+	IsDir    bool       `json:"isDir"`
+	Modified time.Time  `json:"modified"`
+	Hash     string     `json:"hash,omitempty"`
+	Ext      string     `json:"ext"`
+	Chunks   []ChunkRef `json:"chunks,omitempty"` // content-defined chunk index, set when ScanOptions.ChunkLargeFiles applies
+}
+
+// ScanOptions configures ScanDirectoryWithOptions.
+type ScanOptions struct {
+	// ChunkLargeFiles enables content-defined chunking (see ChunkFile) for
+	// files at or above ChunkThreshold, populating FileInfo.Chunks so the
+	// server can dedupe chunks across files instead of re-uploading them.
+	ChunkLargeFiles bool
+	ChunkThreshold  int64
+	Chunk           ChunkOptions
+
+	// Progress, if set, is reported against a count of files scanned.
+	// Left nil, ScanDirectoryWithOptions reports to nothing.
+	Progress progress.Progress
+
+	// Logger, if set, receives contextual events (e.g. a chunking
+	// failure on one large file, which doesn't otherwise fail the scan).
+	// Left nil, ScanDirectoryWithOptions uses log.Default().
+	Logger log.Logger
+}
+
+// DefaultScanOptions returns sensible defaults: chunking enabled for files
+// of 64MiB or larger.
+func DefaultScanOptions() ScanOptions {
+	return ScanOptions{
+		ChunkLargeFiles: true,
+		ChunkThreshold:  64 * 1024 * 1024,
+		Chunk:           DefaultChunkOptions(),
+	}
 }
 
 // ScanResult contains the results of a directory scan
 type ScanResult struct {
-	RootPath    string              `json:"rootPath"`
-	FileCount   int                 `json:"fileCount"`
-	FolderCount int                 `json:"folderCount"`
-	TotalSize   int64               `json:"totalSize"`
-	Files       []FileInfo          `json:"files"`
-	FileTypes   map[string]int      `json:"fileTypes"`
-	LargeFiles  []FileInfo          `json:"largeFiles"`
-	ScannedAt   time.Time           `json:"scannedAt"`
+	RootPath    string         `json:"rootPath"`
+	FileCount   int            `json:"fileCount"`
+	FolderCount int            `json:"folderCount"`
+	TotalSize   int64          `json:"totalSize"`
+	Files       []FileInfo     `json:"files"`
+	FileTypes   map[string]int `json:"fileTypes"`
+	LargeFiles  []FileInfo     `json:"largeFiles"`
+	ScannedAt   time.Time      `json:"scannedAt"`
 }
 
 // Patterns to ignore
@@ -51,13 +84,33 @@ var ignorePatterns = []string{
 	".nuxt",
 }
 
-// ScanDirectory scans a directory and returns file information
+// ScanDirectory scans a directory and returns file information, using
+// DefaultScanOptions.
 func ScanDirectory(root string) (*ScanResult, error) {
+	return ScanDirectoryWithOptions(root, DefaultScanOptions())
+}
+
+// ScanDirectoryWithOptions scans a directory and returns file information,
+// additionally chunking files at or above opts.ChunkThreshold when
+// opts.ChunkLargeFiles is set.
+func ScanDirectoryWithOptions(root string, opts ScanOptions) (*ScanResult, error) {
 	absRoot, err := filepath.Abs(root)
 	if err != nil {
 		return nil, err
 	}
 
+	reporter := opts.Progress
+	if reporter == nil {
+		reporter = progress.Noop{}
+	}
+	reporter.Start(int64(countFiles(absRoot)))
+	defer reporter.Finish()
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
 	result := &ScanResult{
 		RootPath:   absRoot,
 		Files:      make([]FileInfo, 0),
@@ -96,6 +149,7 @@ func ScanDirectory(root string) (*ScanResult, error) {
 		} else {
 			result.FileCount++
 			result.TotalSize += info.Size()
+			reporter.Add(1)
 
 			ext := strings.ToLower(filepath.Ext(name))
 			if ext == "" {
@@ -112,6 +166,14 @@ func ScanDirectory(root string) (*ScanResult, error) {
 				Ext:      ext,
 			}
 
+			if opts.ChunkLargeFiles && info.Size() >= opts.ChunkThreshold {
+				if chunks, err := ChunkFile(path, opts.Chunk); err == nil {
+					fileInfo.Chunks = chunks
+				} else {
+					logger.Warn("chunking failed", log.F("path", relPath), log.F("error", err.Error()))
+				}
+			}
+
 			// Track large files (> 10MB)
 			if info.Size() > 10*1024*1024 {
 				result.LargeFiles = append(result.LargeFiles, fileInfo)
@@ -201,6 +263,38 @@ func FormatBytes(bytes int64) string {
 	return string(rune(bytes/div)) + " " + []string{"KB", "MB", "GB", "TB"}[exp]
 }
 
+// countFiles does a quick pre-pass over root applying the same ignore
+// rules as ScanDirectoryWithOptions, purely to give the progress reporter
+// an accurate total before the real (and more expensive) walk begins.
+func countFiles(root string) int {
+	count := 0
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := info.Name()
+		if strings.HasPrefix(name, ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, pattern := range ignorePatterns {
+			if name == pattern {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if !info.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
 func sortLargeFiles(files []FileInfo) {
 	for i := 0; i < len(files)-1; i++ {
 		for j := i + 1; j < len(files); j++ {
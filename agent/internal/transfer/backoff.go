@@ -0,0 +1,64 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/cloudmigrate/agent/internal/log"
+)
+
+const maxRetryAttempts = 5
+
+// withRetry calls fn up to maxRetryAttempts times in all, backing off
+// exponentially with jitter between attempts when the error looks transient
+// (a network error, a 5xx response, or a 429). Anything else returns
+// immediately.
+func withRetry(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; attempt < maxRetryAttempts; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		if !retryable(err) {
+			return err
+		}
+
+		backoff := time.Duration(1<<uint(attempt)) * 250 * time.Millisecond
+		backoff += time.Duration(rand.Int63n(int64(100 * time.Millisecond)))
+
+		log.FromContext(ctx).Warn("retrying transient transfer error",
+			log.F("attempt", attempt+1), log.F("backoff", backoff.String()), log.F("error", err.Error()))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+	return err
+}
+
+func retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) {
+		code := respErr.HTTPStatusCode()
+		return code >= 500 || code == 429
+	}
+
+	return false
+}
@@ -0,0 +1,117 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+func responseError(statusCode int) *smithyhttp.ResponseError {
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: statusCode}},
+	}
+}
+
+type fakeNetError struct{}
+
+func (fakeNetError) Error() string   { return "fake net error" }
+func (fakeNetError) Timeout() bool   { return true }
+func (fakeNetError) Temporary() bool { return true }
+
+var _ net.Error = fakeNetError{}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error is not retryable", nil, false},
+		{"plain error is not retryable", errors.New("boom"), false},
+		{"net error is retryable", fakeNetError{}, true},
+		{"5xx response is retryable", responseError(503), true},
+		{"429 response is retryable", responseError(429), true},
+		{"404 response is not retryable", responseError(404), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRetrySucceedsWithoutRetryOnNilError(t *testing.T) {
+	calls := 0
+	err := withRetry(context.Background(), func() error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withRetry returned error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+}
+
+func TestWithRetryStopsImmediatelyOnNonRetryableError(t *testing.T) {
+	wantErr := errors.New("permanent failure")
+	calls := 0
+	err := withRetry(context.Background(), func() error {
+		calls++
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("withRetry returned %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times for a non-retryable error, want 1", calls)
+	}
+}
+
+func TestWithRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	err := withRetry(context.Background(), func() error {
+		calls++
+		return fakeNetError{}
+	})
+	if err == nil {
+		t.Fatal("withRetry returned nil, want an error after exhausting retries")
+	}
+	if calls != maxRetryAttempts {
+		t.Errorf("fn called %d times, want %d", calls, maxRetryAttempts)
+	}
+}
+
+func TestWithRetryStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- withRetry(ctx, func() error {
+			calls++
+			cancel()
+			return fakeNetError{}
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("withRetry returned %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("withRetry did not return after context cancellation")
+	}
+	if calls == 0 {
+		t.Error("fn was never called")
+	}
+}
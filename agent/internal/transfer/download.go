@@ -0,0 +1,176 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Download performs a ranged, parallel download of opts.Bucket/opts.Key to
+// opts.Path. When opts.Resume is set and a matching state file exists on
+// disk (same bucket/key/part size/total size), parts already written are
+// skipped instead of re-downloaded.
+//
+// Unlike Upload, there's no per-part checksum to verify against here: S3
+// doesn't expose one for an arbitrary byte range of an object whose ETag
+// may not even be a plain MD5 (multipart-uploaded objects use a composite
+// ETag). Integrity for downloads relies on the TLS transport and S3's own
+// Content-Length accounting.
+func (m *Manager) Download(ctx context.Context, opts Options, onProgress ProgressFunc) error {
+	opts.setDefaults()
+
+	head, err := m.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(opts.Bucket),
+		Key:    aws.String(opts.Key),
+	})
+	if err != nil {
+		return fmt.Errorf("head object: %w", err)
+	}
+	totalSize := aws.ToInt64(head.ContentLength)
+
+	numParts := int((totalSize + opts.PartSize - 1) / opts.PartSize)
+	if numParts == 0 {
+		numParts = 1
+	}
+
+	statePath := stateFile(m.stateDir, "download", opts.Bucket, opts.Key)
+	st := &downloadState{Bucket: opts.Bucket, Key: opts.Key, TotalBytes: totalSize, PartSize: opts.PartSize, DoneParts: make(map[int]bool)}
+
+	resumed := opts.Resume && loadState(statePath, st) && st.PartSize == opts.PartSize && st.TotalBytes == totalSize
+	if !resumed {
+		st.DoneParts = make(map[int]bool)
+		if err := saveState(statePath, st); err != nil {
+			return fmt.Errorf("persist download state: %w", err)
+		}
+	}
+
+	out, err := os.OpenFile(opts.Path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", opts.Path, err)
+	}
+	defer out.Close()
+	if err := out.Truncate(totalSize); err != nil {
+		return fmt.Errorf("truncate %s: %w", opts.Path, err)
+	}
+
+	var stateMu, progressMu sync.Mutex
+	var bytesDone int64
+	for partNum, done := range st.DoneParts {
+		if done {
+			bytesDone += partSizeFor(partNum, numParts, opts.PartSize, totalSize)
+		}
+	}
+
+	start := time.Now()
+	reportProgress := func() {
+		if onProgress == nil {
+			return
+		}
+		progressMu.Lock()
+		done := bytesDone
+		progressMu.Unlock()
+
+		elapsed := time.Since(start).Seconds()
+		var throughput float64
+		if elapsed > 0 {
+			throughput = float64(done) / elapsed
+		}
+		var eta time.Duration
+		if throughput > 0 {
+			eta = time.Duration(float64(totalSize-done)/throughput) * time.Second
+		}
+		onProgress(Progress{BytesTransferred: done, TotalBytes: totalSize, ThroughputBps: throughput, ETA: eta})
+	}
+
+	pending := make(chan int, numParts)
+	for i := 1; i <= numParts; i++ {
+		if st.DoneParts[i] {
+			continue
+		}
+		pending <- i
+	}
+	close(pending)
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, opts.Concurrency)
+
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partNum := range pending {
+				select {
+				case <-workerCtx.Done():
+					return
+				default:
+				}
+
+				offset := int64(partNum-1) * opts.PartSize
+				size := partSizeFor(partNum, numParts, opts.PartSize, totalSize)
+
+				var body []byte
+				err := withRetry(workerCtx, func() error {
+					resp, err := m.client.GetObject(workerCtx, &s3.GetObjectInput{
+						Bucket: aws.String(opts.Bucket),
+						Key:    aws.String(opts.Key),
+						Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+size-1)),
+					})
+					if err != nil {
+						return err
+					}
+					defer resp.Body.Close()
+					body, err = io.ReadAll(resp.Body)
+					return err
+				})
+				if err != nil {
+					sendErr(errCh, fmt.Errorf("download part %d: %w", partNum, err))
+					cancel()
+					return
+				}
+
+				if _, err := out.WriteAt(body, offset); err != nil {
+					sendErr(errCh, fmt.Errorf("write part %d: %w", partNum, err))
+					cancel()
+					return
+				}
+
+				stateMu.Lock()
+				st.DoneParts[partNum] = true
+				saveErr := saveState(statePath, st)
+				stateMu.Unlock()
+				if saveErr != nil {
+					sendErr(errCh, fmt.Errorf("persist state after part %d: %w", partNum, saveErr))
+					cancel()
+					return
+				}
+
+				progressMu.Lock()
+				bytesDone += size
+				progressMu.Unlock()
+				reportProgress()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	if err := <-errCh; err != nil {
+		return err
+	}
+	if workerCtx.Err() != nil {
+		return workerCtx.Err()
+	}
+
+	removeState(statePath)
+	return nil
+}
@@ -0,0 +1,41 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Manager runs Upload/Download transfers against an S3 (or S3-compatible)
+// endpoint, persisting resume state under stateDir.
+type Manager struct {
+	client   *s3.Client
+	stateDir string
+}
+
+// NewManager builds a Manager from the default AWS config (environment,
+// shared config/credentials files, or an attached instance role), optionally
+// pointed at an S3-compatible endpoint instead of real AWS S3.
+func NewManager(ctx context.Context, endpoint, region, stateDir string) (*Manager, error) {
+	var loadOpts []func(*config.LoadOptions) error
+	if region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &Manager{client: client, stateDir: stateDir}, nil
+}
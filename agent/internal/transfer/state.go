@@ -0,0 +1,70 @@
+package transfer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// uploadState is the resumable, on-disk record of an in-progress multipart
+// upload: the upload ID S3 assigned, and the ETag/checksum S3 returned for
+// each part already acknowledged. Resuming skips re-uploading any part
+// present here.
+type uploadState struct {
+	UploadID string             `json:"uploadId"`
+	Bucket   string             `json:"bucket"`
+	Key      string             `json:"key"`
+	PartSize int64              `json:"partSize"`
+	Parts    map[int]partResult `json:"parts"` // part number -> ETag/checksum
+}
+
+// partResult is what S3 returns for one completed UploadPart, and what
+// CompleteMultipartUpload needs back for that part.
+type partResult struct {
+	ETag           string `json:"etag"`
+	ChecksumSHA256 string `json:"checksumSha256"`
+}
+
+// downloadState is the resumable record of an in-progress ranged download:
+// which byte-range parts have already been written to the destination file.
+type downloadState struct {
+	Bucket     string       `json:"bucket"`
+	Key        string       `json:"key"`
+	TotalBytes int64        `json:"totalBytes"`
+	PartSize   int64        `json:"partSize"`
+	DoneParts  map[int]bool `json:"doneParts"`
+}
+
+// stateFile returns the path used to persist resume state for a given
+// bucket/key pair, named by a hash of the pair so arbitrary keys (which may
+// contain slashes) don't have to be sanitized into a filename.
+func stateFile(dir, prefix, bucket, key string) string {
+	sum := sha256.Sum256([]byte(bucket + "/" + key))
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.json", prefix, hex.EncodeToString(sum[:8])))
+}
+
+func loadState(path string, v interface{}) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+func saveState(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func removeState(path string) {
+	os.Remove(path)
+}
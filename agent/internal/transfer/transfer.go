@@ -0,0 +1,62 @@
+// Package transfer implements resumable, chunked S3 (and S3-compatible)
+// multipart uploads and ranged downloads: parallel part workers, automatic
+// retry with exponential backoff, MD5/SHA256 integrity checks on each
+// uploaded part (downloaded parts are not checksummed), and resume state
+// persisted to disk so an interrupted transfer can pick up where it left off
+// instead of restarting from byte zero.
+package transfer
+
+import "time"
+
+// DefaultPartSize and DefaultConcurrency are used when Options leaves
+// PartSize/Concurrency unset.
+const (
+	DefaultPartSize    = 8 * 1024 * 1024 // 8MiB
+	DefaultConcurrency = 4
+)
+
+// Options configures a single Upload or Download.
+type Options struct {
+	Bucket      string
+	Key         string
+	Path        string // local file path (source for Upload, destination for Download)
+	PartSize    int64
+	Concurrency int
+	Resume      bool
+	Endpoint    string // optional S3-compatible endpoint override
+	Region      string
+}
+
+func (o *Options) setDefaults() {
+	if o.PartSize <= 0 {
+		o.PartSize = DefaultPartSize
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultConcurrency
+	}
+}
+
+// Progress is reported periodically while a transfer runs.
+type Progress struct {
+	BytesTransferred int64
+	TotalBytes       int64
+	ThroughputBps    float64
+	ETA              time.Duration
+}
+
+// ProgressFunc receives periodic Progress updates. Upload/Download call it
+// synchronously from a worker goroutine, so implementations should return
+// quickly (e.g. hand off to a channel) rather than block.
+type ProgressFunc func(Progress)
+
+// partSizeFor returns the byte length of part partNum (1-indexed) given the
+// overall part size and total object size - every part is PartSize bytes
+// except the last, which is whatever remains.
+func partSizeFor(partNum, numParts int, partSize, totalSize int64) int64 {
+	offset := int64(partNum-1) * partSize
+	size := partSize
+	if offset+size > totalSize {
+		size = totalSize - offset
+	}
+	return size
+}
@@ -0,0 +1,30 @@
+package transfer
+
+import "testing"
+
+func TestPartSizeFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		partNum   int
+		numParts  int
+		partSize  int64
+		totalSize int64
+		want      int64
+	}{
+		{"single full part exactly fills total", 1, 1, 10, 10, 10},
+		{"first of several full parts", 1, 3, 10, 25, 10},
+		{"middle full part", 2, 3, 10, 25, 10},
+		{"last part is the remainder", 3, 3, 10, 25, 5},
+		{"last part happens to be a full part", 2, 2, 10, 20, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := partSizeFor(tt.partNum, tt.numParts, tt.partSize, tt.totalSize)
+			if got != tt.want {
+				t.Errorf("partSizeFor(%d, %d, %d, %d) = %d, want %d",
+					tt.partNum, tt.numParts, tt.partSize, tt.totalSize, got, tt.want)
+			}
+		})
+	}
+}
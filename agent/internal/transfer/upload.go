@@ -0,0 +1,230 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Upload performs a multipart upload of opts.Path to opts.Bucket/opts.Key.
+// When opts.Resume is set and a matching state file exists on disk, it
+// continues the in-progress upload ID and skips parts already acknowledged
+// by S3 instead of starting over. Every part carries an MD5 Content-MD5
+// header (which S3 verifies server-side) and a SHA256 checksum.
+func (m *Manager) Upload(ctx context.Context, opts Options, onProgress ProgressFunc) error {
+	opts.setDefaults()
+
+	f, err := os.Open(opts.Path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", opts.Path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	totalSize := info.Size()
+
+	numParts := int((totalSize + opts.PartSize - 1) / opts.PartSize)
+	if numParts == 0 {
+		numParts = 1
+	}
+
+	statePath := stateFile(m.stateDir, "upload", opts.Bucket, opts.Key)
+	st := &uploadState{Bucket: opts.Bucket, Key: opts.Key, PartSize: opts.PartSize, Parts: make(map[int]partResult)}
+
+	resumed := opts.Resume && loadState(statePath, st) && st.PartSize == opts.PartSize && st.UploadID != ""
+	if !resumed {
+		out, err := m.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+			Bucket:            aws.String(opts.Bucket),
+			Key:               aws.String(opts.Key),
+			ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+		})
+		if err != nil {
+			return fmt.Errorf("create multipart upload: %w", err)
+		}
+		st.UploadID = aws.ToString(out.UploadId)
+		st.Parts = make(map[int]partResult)
+		if err := saveState(statePath, st); err != nil {
+			return fmt.Errorf("persist upload state: %w", err)
+		}
+	}
+
+	var stateMu sync.Mutex
+	var progressMu sync.Mutex
+	var bytesDone int64
+	for partNum := range st.Parts {
+		bytesDone += partSizeFor(partNum, numParts, opts.PartSize, totalSize)
+	}
+
+	start := time.Now()
+	reportProgress := func() {
+		if onProgress == nil {
+			return
+		}
+		progressMu.Lock()
+		done := bytesDone
+		progressMu.Unlock()
+
+		elapsed := time.Since(start).Seconds()
+		var throughput float64
+		if elapsed > 0 {
+			throughput = float64(done) / elapsed
+		}
+		var eta time.Duration
+		if throughput > 0 {
+			eta = time.Duration(float64(totalSize-done)/throughput) * time.Second
+		}
+		onProgress(Progress{BytesTransferred: done, TotalBytes: totalSize, ThroughputBps: throughput, ETA: eta})
+	}
+
+	pending := make(chan int, numParts)
+	for i := 1; i <= numParts; i++ {
+		if _, done := st.Parts[i]; done {
+			continue
+		}
+		pending <- i
+	}
+	close(pending)
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, opts.Concurrency)
+
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partNum := range pending {
+				select {
+				case <-workerCtx.Done():
+					return
+				default:
+				}
+
+				offset := int64(partNum-1) * opts.PartSize
+				size := partSizeFor(partNum, numParts, opts.PartSize, totalSize)
+
+				buf := make([]byte, size)
+				if _, err := f.ReadAt(buf, offset); err != nil {
+					sendErr(errCh, fmt.Errorf("read part %d: %w", partNum, err))
+					cancel()
+					return
+				}
+
+				md5Sum := md5.Sum(buf)
+				sha256Sum := sha256.Sum256(buf)
+
+				var result partResult
+				err := withRetry(workerCtx, func() error {
+					out, err := m.client.UploadPart(workerCtx, &s3.UploadPartInput{
+						Bucket:            aws.String(opts.Bucket),
+						Key:               aws.String(opts.Key),
+						UploadId:          aws.String(st.UploadID),
+						PartNumber:        aws.Int32(int32(partNum)),
+						Body:              bytes.NewReader(buf),
+						ContentMD5:        aws.String(base64.StdEncoding.EncodeToString(md5Sum[:])),
+						ChecksumSHA256:    aws.String(base64.StdEncoding.EncodeToString(sha256Sum[:])),
+						ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+					})
+					if err != nil {
+						return err
+					}
+					result = partResult{ETag: aws.ToString(out.ETag), ChecksumSHA256: aws.ToString(out.ChecksumSHA256)}
+					return nil
+				})
+				if err != nil {
+					sendErr(errCh, fmt.Errorf("upload part %d: %w", partNum, err))
+					cancel()
+					return
+				}
+
+				stateMu.Lock()
+				st.Parts[partNum] = result
+				saveErr := saveState(statePath, st)
+				stateMu.Unlock()
+				if saveErr != nil {
+					sendErr(errCh, fmt.Errorf("persist state after part %d: %w", partNum, saveErr))
+					cancel()
+					return
+				}
+
+				progressMu.Lock()
+				bytesDone += size
+				progressMu.Unlock()
+				reportProgress()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	if err := <-errCh; err != nil {
+		return m.failUpload(opts, st.UploadID, statePath, err)
+	}
+	if workerCtx.Err() != nil {
+		return m.failUpload(opts, st.UploadID, statePath, workerCtx.Err())
+	}
+
+	completed := make([]types.CompletedPart, 0, numParts)
+	for i := 1; i <= numParts; i++ {
+		completed = append(completed, types.CompletedPart{
+			PartNumber:     aws.Int32(int32(i)),
+			ETag:           aws.String(st.Parts[i].ETag),
+			ChecksumSHA256: aws.String(st.Parts[i].ChecksumSHA256),
+		})
+	}
+
+	if _, err := m.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(opts.Bucket),
+		Key:             aws.String(opts.Key),
+		UploadId:        aws.String(st.UploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	}); err != nil {
+		return fmt.Errorf("complete multipart upload: %w", err)
+	}
+
+	removeState(statePath)
+	return nil
+}
+
+// failUpload aborts the multipart upload on S3 (freeing the storage it was
+// holding) and removes the partial resume state, since an aborted upload ID
+// can never be completed. Uses a fresh background context - the caller's ctx
+// is likely already canceled.
+func (m *Manager) failUpload(opts Options, uploadID, statePath string, cause error) error {
+	if uploadID != "" {
+		_, abortErr := m.client.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(opts.Bucket),
+			Key:      aws.String(opts.Key),
+			UploadId: aws.String(uploadID),
+		})
+		if abortErr != nil {
+			removeState(statePath)
+			return fmt.Errorf("%w (abort multipart upload also failed: %v)", cause, abortErr)
+		}
+	}
+	removeState(statePath)
+	return cause
+}
+
+func sendErr(ch chan<- error, err error) {
+	select {
+	case ch <- err:
+	default:
+	}
+}
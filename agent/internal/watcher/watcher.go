@@ -0,0 +1,418 @@
+// Package watcher streams coalesced batches of filesystem changes under a
+// root directory, built on fsnotify. It exists separately from scanner so
+// the push-notification path (this package) and the poll-and-diff path
+// (scanner.ScanDirectory) can evolve independently - a Watcher doesn't scan
+// file contents or build a ScanResult, it just reports what changed.
+package watcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	defaultDebounce     = 300 * time.Millisecond
+	defaultPollInterval = 10 * time.Second
+	defaultMaxWatches   = 8192
+)
+
+// ignorePatterns mirrors scanner.ScanDirectory's skip rules for directory
+// names, so a Watcher never reports changes scanner would have ignored
+// anyway.
+var ignorePatterns = []string{
+	".git",
+	".svn",
+	"node_modules",
+	"__pycache__",
+	".venv",
+	"venv",
+	".idea",
+	".vscode",
+	"vendor",
+	"target",
+	"build",
+	"dist",
+	".next",
+	".nuxt",
+}
+
+func shouldIgnore(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	for _, pattern := range ignorePatterns {
+		if name == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// changeType is the internal classification of a pending path change,
+// collapsed into one of Batch's three slices once a batch is flushed.
+type changeType string
+
+const (
+	changeAdded    changeType = "added"
+	changeModified changeType = "modified"
+	changeRemoved  changeType = "removed"
+)
+
+// Options configures a Watcher's coverage and limits.
+type Options struct {
+	// MaxDepth caps how many directory levels below root are watched or
+	// polled; 0 means unlimited.
+	MaxDepth int
+	// MaxWatches caps how many directory watch descriptors the Watcher
+	// will hold before it stops adding fsnotify watches and falls back to
+	// polling the whole tree instead. 0 uses a conservative default,
+	// since platforms like inotify impose their own OS-wide per-user cap
+	// that a deep or wide tree can exhaust.
+	MaxWatches int
+	// Debounce coalesces a burst of events against the same path into a
+	// single entry in the next batch.
+	Debounce time.Duration
+	// PollInterval is how often the polling fallback rescans the tree.
+	PollInterval time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxWatches <= 0 {
+		o.MaxWatches = defaultMaxWatches
+	}
+	if o.Debounce <= 0 {
+		o.Debounce = defaultDebounce
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultPollInterval
+	}
+	return o
+}
+
+// Batch is one coalesced set of filesystem changes under root, relative to
+// root and slash-separated. It's built to map directly onto a "delta" wire
+// message: {"type":"delta","added":[...],"modified":[...],"removed":[...]}.
+type Batch struct {
+	Added    []string
+	Modified []string
+	Removed  []string
+}
+
+func (b Batch) empty() bool {
+	return len(b.Added) == 0 && len(b.Modified) == 0 && len(b.Removed) == 0
+}
+
+type pollEntry struct {
+	size    int64
+	modTime time.Time
+}
+
+// Watcher streams coalesced batches of filesystem changes under root,
+// recursing into (and keeping watches on) every subdirectory it doesn't
+// ignore. It watches via fsnotify where possible and degrades to polling
+// the whole tree once MaxWatches (or the platform's own watch-descriptor
+// limit) is hit, rather than erroring out or silently missing changes past
+// that point.
+type Watcher struct {
+	root string
+	opts Options
+
+	fsw     *fsnotify.Watcher
+	batches chan Batch
+	done    chan struct{}
+	closeMu sync.Mutex
+	closed  bool
+
+	// The following fields are only ever touched from the run() goroutine
+	// after New returns, so they need no locking.
+	watches int
+	pending map[string]changeType
+	polling bool
+}
+
+// New starts watching root (and its current and future subdirectories,
+// subject to opts) for changes. Call Close when done to release the
+// underlying inotify/kqueue handles.
+func New(root string, opts Options) (*Watcher, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create fsnotify watcher: %w", err)
+	}
+
+	w := &Watcher{
+		root:    absRoot,
+		opts:    opts,
+		fsw:     fsw,
+		batches: make(chan Batch, 16),
+		done:    make(chan struct{}),
+		pending: make(map[string]changeType),
+	}
+
+	w.addTree(absRoot, 0)
+
+	go w.run()
+	return w, nil
+}
+
+// Batches returns the channel of coalesced filesystem changes. It is
+// closed once Close is called.
+func (w *Watcher) Batches() <-chan Batch { return w.batches }
+
+// Close stops the watcher and closes the Batches channel.
+func (w *Watcher) Close() error {
+	w.closeMu.Lock()
+	defer w.closeMu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+	return w.fsw.Close()
+}
+
+// addTree registers a watch on dir and every non-ignored subdirectory
+// beneath it, up to opts.MaxDepth. Once MaxWatches is reached, or an Add
+// fails (most often ENOSPC from the OS's inotify instance limit), it stops
+// adding watches and switches the whole Watcher to polling instead of
+// returning an error - a tree too large to watch natively still gets
+// covered, just on a slower cadence.
+func (w *Watcher) addTree(dir string, depth int) {
+	if w.polling {
+		return
+	}
+	if w.opts.MaxDepth > 0 && depth > w.opts.MaxDepth {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return // best-effort: skip dirs we can't read
+	}
+
+	if w.watches >= w.opts.MaxWatches {
+		w.startPolling()
+		return
+	}
+	if err := w.fsw.Add(dir); err != nil {
+		w.startPolling()
+		return
+	}
+	w.watches++
+
+	for _, entry := range entries {
+		if !entry.IsDir() || shouldIgnore(entry.Name()) {
+			continue
+		}
+		w.addTree(filepath.Join(dir, entry.Name()), depth+1)
+	}
+}
+
+// startPolling switches the Watcher from fsnotify to periodic polling.
+// Already-registered fsnotify watches are left in place (harmless, if
+// redundant) until Close; run() picks the polling path on its own once
+// this flag is set.
+func (w *Watcher) startPolling() {
+	w.polling = true
+}
+
+func (w *Watcher) depthOf(path string) int {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(filepath.ToSlash(rel), "/") + 1
+}
+
+func (w *Watcher) run() {
+	defer close(w.batches)
+
+	if w.polling {
+		w.runPolling()
+		return
+	}
+
+	debounceTimer := time.NewTimer(w.opts.Debounce)
+	if !debounceTimer.Stop() {
+		<-debounceTimer.C
+	}
+	pendingActive := false
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			_ = err // best-effort: a dropped event isn't fatal to the watcher
+
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.recordEvent(ev)
+			if !pendingActive {
+				pendingActive = true
+				debounceTimer.Reset(w.opts.Debounce)
+			}
+
+			if w.polling {
+				// addTree (called from recordEvent for a newly created
+				// directory) just hit the watch cap - hand off to polling
+				// for everything from here on.
+				w.runPolling()
+				return
+			}
+
+		case <-debounceTimer.C:
+			pendingActive = false
+			w.flush()
+		}
+	}
+}
+
+func (w *Watcher) recordEvent(ev fsnotify.Event) {
+	name := filepath.Base(ev.Name)
+	if shouldIgnore(name) {
+		return
+	}
+
+	// A new directory needs its own watch so nested changes are seen too.
+	if ev.Op&fsnotify.Create != 0 {
+		if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+			w.addTree(ev.Name, w.depthOf(ev.Name))
+			return
+		}
+	}
+
+	rel, err := filepath.Rel(w.root, ev.Name)
+	if err != nil {
+		rel = ev.Name
+	}
+	rel = filepath.ToSlash(rel)
+
+	switch {
+	case ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+		w.pending[rel] = changeRemoved
+	case ev.Op&fsnotify.Create != 0:
+		w.pending[rel] = changeAdded
+	default:
+		if w.pending[rel] != changeAdded {
+			w.pending[rel] = changeModified
+		}
+	}
+}
+
+func (w *Watcher) flush() {
+	if len(w.pending) == 0 {
+		return
+	}
+
+	var batch Batch
+	for path, ct := range w.pending {
+		switch ct {
+		case changeAdded:
+			batch.Added = append(batch.Added, path)
+		case changeModified:
+			batch.Modified = append(batch.Modified, path)
+		case changeRemoved:
+			batch.Removed = append(batch.Removed, path)
+		}
+	}
+	w.pending = make(map[string]changeType)
+
+	select {
+	case w.batches <- batch:
+	case <-w.done:
+	}
+}
+
+// runPolling periodically rescans the tree and diffs consecutive
+// snapshots, used once fsnotify coverage has been exhausted (see
+// startPolling). It keeps running until Close.
+func (w *Watcher) runPolling() {
+	snapshot := w.scanSnapshot()
+
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			next := w.scanSnapshot()
+			batch := diffSnapshots(snapshot, next)
+			snapshot = next
+			if batch.empty() {
+				continue
+			}
+			select {
+			case w.batches <- batch:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func (w *Watcher) scanSnapshot() map[string]pollEntry {
+	out := make(map[string]pollEntry)
+	filepath.Walk(w.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != w.root && shouldIgnore(info.Name()) {
+				return filepath.SkipDir
+			}
+			if w.opts.MaxDepth > 0 && w.depthOf(path) > w.opts.MaxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if shouldIgnore(info.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(w.root, path)
+		if err != nil {
+			return nil
+		}
+		out[filepath.ToSlash(rel)] = pollEntry{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return out
+}
+
+func diffSnapshots(old, next map[string]pollEntry) Batch {
+	var batch Batch
+	for path, entry := range next {
+		prev, existed := old[path]
+		switch {
+		case !existed:
+			batch.Added = append(batch.Added, path)
+		case prev.size != entry.size || !prev.modTime.Equal(entry.modTime):
+			batch.Modified = append(batch.Modified, path)
+		}
+	}
+	for path := range old {
+		if _, still := next[path]; !still {
+			batch.Removed = append(batch.Removed, path)
+		}
+	}
+	return batch
+}